@@ -0,0 +1,2 @@
+go build -ldflags="-s -w --extldflags '-static -fpic'" -o ./output/LogCollector.exe
+upx --best ./output/LogCollector.exe
@@ -0,0 +1,35 @@
+/**
+ * @Author: Joey
+ * @Description:
+ * @Create Date: 2026/10/18 15:00
+ */
+
+package main
+
+import (
+	_ "embed"
+	"time"
+)
+
+//go:embed logCollector.yaml
+var configData []byte
+
+type config struct {
+	Module   string
+	Addr     string
+	UID      string
+	PWD      string
+	PreFix   string
+	TimeOut  time.Duration
+	Dir      string
+	PreFixes []string
+	MaxSize  int64         // 存储总大小 MB
+	MaxAge   time.Duration // 日志保留小时数
+}
+
+var MyConfig = config{
+	Module:  "LogCollector",
+	Addr:    "ws://127.0.0.1:5002/ws",
+	TimeOut: 1000,
+	Dir:     "logStore",
+}
@@ -0,0 +1,61 @@
+/**
+ * @Author: Joey
+ * @Description: 日志收集服务
+ * @Create Date: 2026/10/18 15:00
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	easyCon "github.com/qiu-tec/easy-con.golang"
+	"github.com/spf13/viper"
+)
+
+func init() {
+	cfgFile := "./logCollector.yaml"
+	if _, err := os.Stat(cfgFile); os.IsNotExist(err) {
+		_ = os.WriteFile(cfgFile, configData, 0644)
+	}
+
+	viper.SetConfigFile(cfgFile)
+	viper.SetConfigType("yaml")
+	err := viper.ReadInConfig()
+	if err != nil {
+		panic(fmt.Errorf("Fatal error config file: %s \n", err))
+	}
+	js, err := json.Marshal(viper.Get("CollectorConfig"))
+	if err != nil {
+		panic(err)
+	}
+	err = json.Unmarshal(js, &MyConfig)
+	if err != nil {
+		panic(err)
+	}
+	MyConfig.TimeOut = MyConfig.TimeOut * time.Millisecond
+	MyConfig.MaxSize = MyConfig.MaxSize << 20
+	MyConfig.MaxAge = MyConfig.MaxAge * time.Hour
+}
+
+func main() {
+	setting := easyCon.NewDefaultMqttSetting(MyConfig.Module, MyConfig.Addr)
+	setting.UID = MyConfig.UID
+	setting.PWD = MyConfig.PWD
+	setting.PreFix = MyConfig.PreFix
+	setting.TimeOut = MyConfig.TimeOut
+	_, err := easyCon.NewMqttLogCollector(setting, easyCon.LogCollectorSetting{
+		Dir:      MyConfig.Dir,
+		PreFixes: MyConfig.PreFixes,
+		MaxSize:  MyConfig.MaxSize,
+		MaxAge:   MyConfig.MaxAge,
+	}, easyCon.AdapterCallBack{})
+	if err != nil {
+		panic(err)
+	}
+	var block = make(chan struct{})
+	_ = <-block
+}
@@ -0,0 +1,115 @@
+/**
+ * @Author: Joey
+ * @Description: 跨模块请求取消，请求方放弃等待时通知响应方取消正在执行的处理函数
+ * @Create Date: 2026/10/18 09:30
+ */
+
+package easyCon
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// CtxReqHandler 可感知取消的请求处理函数，请求方放弃等待时 ctx 被取消
+type CtxReqHandler func(ctx context.Context, pack PackReq) (EResp, []byte)
+
+// IReqCtxHandler 可感知取消的请求处理器
+type IReqCtxHandler interface {
+	ServeReqCtx(ctx context.Context, pack PackReq) (EResp, []byte)
+}
+
+// ServeReq 使 CtxReqHandler 实现 IReqHandler
+func (f CtxReqHandler) ServeReq(pack PackReq) (EResp, []byte) {
+	return f(context.Background(), pack)
+}
+
+// ServeReqCtx 使 CtxReqHandler 实现 IReqCtxHandler
+func (f CtxReqHandler) ServeReqCtx(ctx context.Context, pack PackReq) (EResp, []byte) {
+	return f(ctx, pack)
+}
+
+// HandleCtx 注册可感知取消的路由处理函数
+func (router *Router) HandleCtx(route string, f CtxReqHandler) {
+	router.Handle(route, f)
+}
+
+// canceledKeep 先于请求到达的取消记录保留时长
+const canceledKeep = time.Minute
+
+// reqCanceler 记录正在处理的请求，收到取消包时取消其ctx
+type reqCanceler struct {
+	mu       sync.Mutex
+	running  map[string]context.CancelFunc
+	canceled map[string]time.Time // 尚未开始处理就被取消的请求
+}
+
+func newReqCanceler() *reqCanceler {
+	return &reqCanceler{
+		running:  make(map[string]context.CancelFunc),
+		canceled: make(map[string]time.Time),
+	}
+}
+
+func cancelKey(pack PackReq) string {
+	return pack.From + "#" + strconv.FormatUint(pack.Id, 10)
+}
+
+// start 登记开始处理的请求，返回其ctx与结束时的清理函数
+// 请求在排队期间已被取消时返回的ctx已处于取消状态
+func (c *reqCanceler) start(parent context.Context, pack PackReq) (context.Context, func()) {
+	key := cancelKey(pack)
+	ctx, cancel := context.WithCancel(parent)
+	c.mu.Lock()
+	if _, b := c.canceled[key]; b {
+		delete(c.canceled, key)
+		cancel()
+	} else {
+		c.running[key] = cancel
+	}
+	c.mu.Unlock()
+	return ctx, func() {
+		c.mu.Lock()
+		delete(c.running, key)
+		c.mu.Unlock()
+		cancel()
+	}
+}
+
+// cancel 取消请求
+func (c *reqCanceler) cancel(pack PackReq) {
+	key := cancelKey(pack)
+	now := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if f, b := c.running[key]; b {
+		f()
+		return
+	}
+	for k, t := range c.canceled {
+		if now.Sub(t) > canceledKeep {
+			delete(c.canceled, k)
+		}
+	}
+	c.canceled[key] = now
+}
+
+// sendCancel 通知响应方取消请求
+func (adapter *coreAdapter) sendCancel(pack PackReq) {
+	cancelPack := pack
+	cancelPack.PType = EPTypeCancel
+	cancelPack.Content = nil
+	e := adapter.engineCallback.OnPublish(adapter.reqTopic(pack.To), false, &cancelPack)
+	if e != nil {
+		adapter.Err("CANCEL send error", e)
+	}
+}
+
+// onCancelRec 收到取消包
+func (adapter *coreAdapter) onCancelRec(pack PackReq) {
+	atomic.AddUint64(&adapter.stats.reqCanceled, 1)
+	adapter.canceler.cancel(pack)
+}
@@ -7,9 +7,7 @@
 package easyCon
 
 import (
-	"fmt"
 	"strings"
-	"time"
 )
 
 type topicBack struct {
@@ -24,8 +22,12 @@ type cgoAdapter struct {
 	readChan    chan []byte
 }
 
+// onRead 收到数据，停止后丢弃，避免阻塞 Broker
 func (adapter *cgoAdapter) onRead(raw []byte) {
-	adapter.readChan <- raw
+	select {
+	case adapter.readChan <- raw:
+	case <-adapter.stopSignal():
+	}
 }
 func NewCgoAdapter(setting CoreSetting, callback AdapterCallBack, onWrite func([]byte) error) (IAdapter, func([]byte)) {
 	// 默认情况下，localBroker 与 onWrite 相同
@@ -42,7 +44,7 @@ func NewCgoAdapterWithBroker(setting CoreSetting, callback AdapterCallBack, onWr
 		readChan:    make(chan []byte, setting.ChannelBufferSize),
 	}
 	ecb := EngineCallback{
-		OnLink:       func() { adapter.onConnected() },
+		OnLink:       adapter.onLink,
 		OnStop:       func() (bool, error) { return true, nil },
 		OnSubscribe:  adapter.onSubscribe,
 		OnPublish:    adapter.onPublish,
@@ -50,7 +52,7 @@ func NewCgoAdapterWithBroker(setting CoreSetting, callback AdapterCallBack, onWr
 	}
 
 	adapter.coreAdapter = newCoreAdapter(setting, ecb, callback)
-	go adapter.readLoop()
+	adapter.Start()
 	return adapter, adapter.onRead
 }
 
@@ -58,6 +60,9 @@ func (adapter *cgoAdapter) generateTopic(pack IPack) string {
 	prefix := adapter.setting.PreFix
 	switch p := pack.(type) {
 	case *PackReq:
+		if isBroadcast(p.To) {
+			return BuildBroadcastReqTopic(prefix)
+		}
 		return BuildReqTopic(prefix, p.To)
 	case *PackResp:
 		// 对于响应，使用 To 字段（目标）而不是 From 字段（发送者）
@@ -84,13 +89,20 @@ type IClient struct {
 //	onWrite func([]byte) error
 //}
 
-func (adapter *cgoAdapter) readLoop() {
+// onLink 每轮启动时开始读取并视为已连接
+func (adapter *cgoAdapter) onLink() {
+	adapter.wg.Add(1)
+	go adapter.readLoop(adapter.stopSignal())
+	adapter.onConnected()
+}
+
+func (adapter *cgoAdapter) readLoop(stop <-chan interface{}) {
+	defer adapter.wg.Done()
 	for {
 		select {
-		case <-adapter.stopChan:
+		case <-stop:
 			return
 		case rawPack := <-adapter.readChan:
-			now := time.Now().Format("15:04:05.000")
 			// 直接解析新协议格式
 			pack, err := UnmarshalPack(rawPack)
 			if err != nil {
@@ -100,7 +112,7 @@ func (adapter *cgoAdapter) readLoop() {
 
 			// 从Header生成topic
 			topic := adapter.generateTopic(pack)
-			fmt.Printf("[%s][CgoAdapter-readLoop] Module=%s Received pack: topic=%s type=%s\n", now, adapter.setting.Module, topic, pack.GetType())
+			adapter.tracer.debug(ETraceCgo, "received pack", "module", adapter.setting.Module, "topic", topic, "type", pack.GetType())
 
 			// 匹配处理函数
 			adapter.mu.Lock()
@@ -108,7 +120,6 @@ func (adapter *cgoAdapter) readLoop() {
 			adapter.mu.Unlock()
 
 			if b {
-				fmt.Printf("[%s][CgoAdapter-readLoop] Exact match found for topic=%s, calling callback\n", now, topic)
 				t.Func(pack)
 			} else {
 				// 尝试通配符匹配：从最长前缀开始，逐步缩短
@@ -120,14 +131,14 @@ func (adapter *cgoAdapter) readLoop() {
 					t, b = adapter.topics[wildcardTopic]
 					adapter.mu.Unlock()
 					if b {
-						fmt.Printf("[%s][CgoAdapter-readLoop] Wildcard match found: %s -> %s, calling callback\n", now, topic, wildcardTopic)
+						adapter.tracer.debug(ETraceCgo, "wildcard match", "topic", topic, "subscribed", wildcardTopic)
 						t.Func(pack)
 						matched = true
 						break
 					}
 				}
 				if !matched {
-					fmt.Printf("[%s][CgoAdapter-readLoop] No match found for topic=%s\n", now, topic)
+					adapter.tracer.debug(ETraceCgo, "no subscription matched", "topic", topic)
 				}
 			}
 		}
@@ -139,8 +150,7 @@ func (adapter *cgoAdapter) onSubscribe(topic string, pType EPType, f func(IPack)
 	adapter.topics[topic] = topicBack{EType: pType, Func: f}
 	adapter.mu.Unlock()
 
-	now := time.Now().Format("15:04:05.000")
-	fmt.Printf("[%s][cgoAdapter-onSubscribe] Module=%s Topic=%s localBroker=%v\n", now, adapter.setting.Module, topic, adapter.localBroker != nil)
+	adapter.tracer.debug(ETraceCgo, "subscribe", "module", adapter.setting.Module, "topic", topic)
 
 	// 如果有 localBroker（CgoBroker），发送订阅请求到本地 Broker
 	if adapter.localBroker != nil {
@@ -149,9 +159,7 @@ func (adapter *cgoAdapter) onSubscribe(topic string, pType EPType, f func(IPack)
 		// To字段为"Broker"，接收方会生成"Request/Broker"topic
 		err := adapter.localBroker(js)
 		if err != nil {
-			fmt.Printf("[%s]:CGO Subscribe Failed because %s\r\n", now, err.Error())
-		} else {
-			fmt.Printf("[%s][cgoAdapter-onSubscribe] Sent subscribe request to CgoBroker: topic=%s\n", now, topic)
+			adapter.tracer.error("cgo subscribe failed", "topic", topic, "err", err)
 		}
 	}
 }
@@ -7,10 +7,8 @@
 package easyCon
 
 import (
-	"fmt"
 	"strings"
 	"sync"
-	"time"
 )
 
 type CgoBroker struct {
@@ -20,6 +18,7 @@ type CgoBroker struct {
 	onError func(err error)
 	// onNoticeSubscribe 当模块订阅通知时的回调
 	onNoticeSubscribe func(route, module string)
+	tracer            tracer
 }
 
 func NewCgoBroker() CgoBroker {
@@ -42,9 +41,23 @@ func (broker *CgoBroker) SetNoticeSubscribeCallback(cb func(route, module string
 	broker.onNoticeSubscribe = cb
 }
 
+// SetLogger 设置内部运行日志，isTrace 为 true 时输出转发与订阅的调试跟踪
+func (broker *CgoBroker) SetLogger(logger ITraceLogger, isTrace bool) {
+	var scopes []ETraceScope
+	if isTrace {
+		scopes = []ETraceScope{ETraceBroker}
+	}
+	broker.lock.Lock()
+	defer broker.lock.Unlock()
+	broker.tracer = newTracer(logger, scopes)
+}
+
 func (broker *CgoBroker) generateTopic(pack IPack) string {
 	switch p := pack.(type) {
 	case *PackReq:
+		if isBroadcast(p.To) {
+			return ReqTopic + BroadcastModule // "Request/*"
+		}
 		return ReqTopic + p.To // "Request/" + To
 	case *PackResp:
 		// 对于响应，使用 To 字段（目标）而不是 From 字段（发送者）
@@ -89,16 +102,13 @@ func (broker *CgoBroker) Publish(raw []byte) error {
 }
 
 func (broker *CgoBroker) RegClient(id string, onRead func([]byte)) {
-	now := time.Now().Format("15:04:05.000")
 	broker.lock.Lock()
 	defer broker.lock.Unlock()
 	broker.clients[id] = onRead
-	fmt.Printf("[%s][CgoBroker-RegClient] Registered module=%s onRead=%v\n", now, id, onRead != nil)
+	broker.tracer.debug(ETraceBroker, "register client", "module", id)
 }
 
 func (broker *CgoBroker) onSend(topic string, raw []byte) {
-	now := time.Now().Format("15:04:05.000")
-	fmt.Printf("[%s][CgoBroker-onSend] topic=%s\n", now, topic)
 	var modulesToNotify []func([]byte)
 
 	// 用于去重的 map，避免同一个模块被多次通知
@@ -152,21 +162,20 @@ func (broker *CgoBroker) onSend(topic string, raw []byte) {
 		}
 	}
 
+	// tracer 可能被 SetLogger 替换，在锁内取出
+	t := broker.tracer
 	broker.lock.RUnlock()
-	fmt.Printf("[%s][CgoBroker-onSend] Found %d subscribers\n", now, len(modulesToNotify))
+	t.debug(ETraceBroker, "deliver", "topic", topic, "subscribers", len(modulesToNotify))
 
 	// 直接传递新协议格式数据
 	for _, f := range modulesToNotify {
 		f(raw)
 	}
-	fmt.Printf("[%s][CgoBroker-onSend] Delivered to %d subscribers\n", now, len(modulesToNotify))
 }
 func (broker *CgoBroker) onReq(pack PackReq) (EResp, []byte) {
 	switch pack.Route {
 	case "Subscribe":
 		topic := string(pack.Content)
-		now := time.Now().Format("15:04:05.000")
-		fmt.Printf("[%s][CgoBroker-onReq] Subscribe request: Module=%s Topic=%s\n", now, pack.From, topic)
 
 		broker.lock.Lock()
 		defer broker.lock.Unlock()
@@ -175,11 +184,7 @@ func (broker *CgoBroker) onReq(pack PackReq) (EResp, []byte) {
 
 		// 获取模块的回调函数（如果已注册）
 		callback, registered := broker.clients[module]
-		if !registered {
-			fmt.Printf("[%s][CgoBroker-onReq] Subscribe: Module %s NOT registered with CgoBroker!\n", now, module)
-		} else {
-			fmt.Printf("[%s][CgoBroker-onReq] Subscribe: Module %s IS registered\n", now, module)
-		}
+		broker.tracer.debug(ETraceBroker, "subscribe", "module", module, "topic", topic, "registered", registered)
 
 		if broker.topics[topic] == nil {
 			broker.topics[topic] = make(map[string]interface{})
@@ -190,10 +195,8 @@ func (broker *CgoBroker) onReq(pack PackReq) (EResp, []byte) {
 		// 否则只存储模块名，等注册时再关联
 		if registered {
 			m[module] = callback
-			fmt.Printf("[%s][CgoBroker-onReq] Subscribe: Registered %s for topic %s\n", now, module, topic)
 		} else {
 			m[module] = nil // 占位符，表示已订阅但回调函数还未注册
-			fmt.Printf("[%s][CgoBroker-onReq] Subscribe: Placeholder for %s on topic %s (waiting for registration)\n", now, module, topic)
 		}
 
 		// 检测是否为Notice订阅，如果是则通知Proxy
@@ -201,11 +204,9 @@ func (broker *CgoBroker) onReq(pack PackReq) (EResp, []byte) {
 		if broker.onNoticeSubscribe != nil && registered {
 			if strings.HasPrefix(topic, NoticeTopic+"/") {
 				route := strings.TrimPrefix(topic, NoticeTopic+"/")
-				fmt.Printf("[%s][CgoBroker-onReq] Notice subscribe: Route=%s Module=%s\n", now, route, module)
 				broker.onNoticeSubscribe(route, module)
 			} else if strings.HasPrefix(topic, RetainNoticeTopic+"/") {
 				route := strings.TrimPrefix(topic, RetainNoticeTopic+"/")
-				fmt.Printf("[%s][CgoBroker-onReq] RetainNotice subscribe: Route=%s Module=%s\n", now, route, module)
 				broker.onNoticeSubscribe(route, module)
 			}
 		}
@@ -0,0 +1,83 @@
+/**
+ * @Author: Joey
+ * @Description: 编解码器，用于类型化请求在对象与 Content 之间转换
+ * @Create Date: 2026/10/17 15:30
+ */
+
+package easyCon
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+)
+
+// Codec 编解码器接口
+type Codec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+var (
+	// JsonCodec JSON编解码，默认编解码器
+	JsonCodec Codec = jsonCodec{}
+	// GobCodec gob编解码，仅适用于Go模块之间
+	GobCodec Codec = gobCodec{}
+	// RawCodec 原始字节，只支持 []byte 与 string
+	RawCodec Codec = rawCodec{}
+)
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+type gobCodec struct{}
+
+func (gobCodec) Marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (gobCodec) Unmarshal(data []byte, v any) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+type rawCodec struct{}
+
+func (rawCodec) Marshal(v any) ([]byte, error) {
+	switch d := v.(type) {
+	case []byte:
+		return d, nil
+	case string:
+		return []byte(d), nil
+	case *[]byte:
+		return *d, nil
+	case *string:
+		return []byte(*d), nil
+	case nil:
+		return nil, nil
+	}
+	return nil, fmt.Errorf("raw codec can not marshal %T", v)
+}
+
+func (rawCodec) Unmarshal(data []byte, v any) error {
+	switch d := v.(type) {
+	case *[]byte:
+		*d = data
+		return nil
+	case *string:
+		*d = string(data)
+		return nil
+	}
+	return fmt.Errorf("raw codec can not unmarshal into %T", v)
+}
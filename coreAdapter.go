@@ -7,10 +7,11 @@
 package easyCon
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"runtime/debug"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -20,19 +21,43 @@ type coreAdapter struct {
 	respDict           map[uint64]chan PackResp
 	mu                 sync.RWMutex
 	noticeChan         chan PackNotice
+	noticeHighChan     chan PackNotice
 	retainNoticeChan   chan PackNotice
 	reqChan            chan PackReq
+	reqHighChan        chan PackReq
 	respChan           chan PackResp
 	stopChan           chan interface{}
 	logChan            chan PackLog
 	startWaitChan      chan interface{}
 	wg                 *sync.WaitGroup
 	startOnce          sync.Once
-	isLinked           bool
+	isLinked           atomic.Bool
 	noticeTopics       map[string]interface{}
 	retainNoticeTopics map[string]interface{}
+	linkTopics         map[string]linkTopic // 内部订阅的主题，每次连接后重新订阅
 	engineCallback     EngineCallback
 	adapterCallback    AdapterCallBack
+	router             *Router
+	interceptors       []Interceptor
+	interceptorMu      sync.RWMutex
+	idemCache          *idemCache
+	canceler           *reqCanceler
+	stats              *adapterStats
+	logLevel           atomic.Value
+	presence           *presenceTracker
+	routeLimiter       *routeLimiter
+	rateLimiter        *rateLimiter
+	tracer             tracer
+	fileLog            *fileLogger // 本地日志文件，未启用时为空
+	drainMu            sync.RWMutex
+	stopping           bool           // 停止中，不再接受新请求
+	handlers           sync.WaitGroup // 处理中的请求
+	closing            chan struct{}  // 停止时关闭，等待响应的调用立即失败
+	lifeMu             sync.Mutex     // 串行化 Start Stop Reset
+	statusMu           sync.RWMutex
+	status             EStatus
+	statusQueue        []EStatus // 待通知的状态切换
+	isNotifying        bool      // 正在发出状态通知
 }
 
 // newCoreAdapter 创建 适配器核心
@@ -48,21 +73,39 @@ func newCoreAdapter(setting CoreSetting, engineCallback EngineCallback, adapterC
 		respDict:           make(map[uint64]chan PackResp),
 		mu:                 sync.RWMutex{},
 		reqChan:            make(chan PackReq, bufferSize),
+		reqHighChan:        make(chan PackReq, bufferSize),
 		respChan:           make(chan PackResp, bufferSize),
 		noticeChan:         make(chan PackNotice, bufferSize),
+		noticeHighChan:     make(chan PackNotice, bufferSize),
 		retainNoticeChan:   make(chan PackNotice, bufferSize),
-		stopChan:           make(chan interface{}),
 		logChan:            make(chan PackLog, bufferSize),
 		wg:                 &sync.WaitGroup{},
 		noticeTopics:       make(map[string]interface{}),
 		retainNoticeTopics: make(map[string]interface{}),
+		linkTopics:         make(map[string]linkTopic),
 		engineCallback:     engineCallback,
 		adapterCallback:    adapterCallBack,
 		startWaitChan:      make(chan interface{}),
+		router:             NewRouter(),
+		canceler:           newReqCanceler(),
+		stats:              newAdapterStats(),
+		presence:           newPresenceTracker(),
+		routeLimiter:       newRouteLimiter(setting.RouteConcurrency),
+		rateLimiter:        newRateLimiter(setting.RateLimit),
+		tracer:             newTracer(setting.Logger, setting.TraceScopes),
 	}
 	adapter.setting = setting
-	adapter.link()
-
+	adapter.logLevel.Store(setting.LogLevel)
+	if setting.IsTrackPresence || setting.IsFastFail {
+		adapter.presence.tracking = true
+	}
+	if setting.IdempotentTTL > 0 {
+		adapter.idemCache = newIdemCache(setting.IdempotentTTL)
+	}
+	if setting.LogFile.Dir != "" || setting.LogMode == ELogModeFile {
+		adapter.fileLog = newFileLogger(setting.Module, setting.LogFile)
+	}
+	adapter.status = EStatusStopped
 	return adapter
 }
 func (adapter *coreAdapter) waitLink() {
@@ -71,86 +114,125 @@ func (adapter *coreAdapter) waitLink() {
 	}
 }
 
-// Stop 停止
+// Stop 停止：拒绝新请求并等待处理中的请求完成（最长 DrainTimeout），等待响应的调用立即失败，然后断开连接
+// 已停止时直接返回，可并发、重复调用
 func (adapter *coreAdapter) Stop() {
-	go func() {
-		//time.Sleep(10)
-		adapter.stopChan <- struct{}{}
-	}()
+	defer adapter.notifyStatus()
+	adapter.lifeMu.Lock()
+	defer adapter.lifeMu.Unlock()
+	adapter.stop()
+}
+
+func (adapter *coreAdapter) stop() {
+	if adapter.Status() == EStatusStopped {
+		return
+	}
+	adapter.setStatus(EStatusStopping)
+	if adapter.isLinked.Load() { // 下线通知
+		adapter.sendPresence(false)
+	}
+	adapter.drain()
+	// 关闭本轮的停止信号，所有协程都能收到
+	adapter.mu.Lock()
+	close(adapter.stopChan)
+	adapter.mu.Unlock()
 	adapter.wg.Wait()
 	if adapter.engineCallback.OnStop != nil {
-		b, err := adapter.engineCallback.OnStop()
+		_, err := adapter.engineCallback.OnStop()
 		if err != nil {
-			adapter.sendLog(newLogPack(adapter.setting.Module, ELogLevelError, "OnStop error"))
-		}
-		if b {
-			if adapter.adapterCallback.OnStatusChanged != nil {
-				adapter.adapterCallback.OnStatusChanged(EStatusStopped)
-			}
-			return
+			adapter.log(0, ELogLevelError, "OnStop error", err, nil)
 		}
 	}
-	if adapter.adapterCallback.OnStatusChanged != nil {
-		adapter.adapterCallback.OnStatusChanged(EStatusStopped)
+	if adapter.fileLog != nil {
+		adapter.fileLog.close()
 	}
+	adapter.setStatus(EStatusStopped)
 }
 
 // Reset 重启
 func (adapter *coreAdapter) Reset() {
-	adapter.Stop()
-	adapter.link()
+	defer adapter.notifyStatus()
+	adapter.lifeMu.Lock()
+	defer adapter.lifeMu.Unlock()
+	adapter.stop()
+	adapter.start()
 }
 
 // Req 请求并等待响应
 func (adapter *coreAdapter) Req(module, route string, content []byte) PackResp {
-	if !adapter.isLinked {
+	if !adapter.isLinked.Load() {
 		return PackResp{
 			RespCode: ERespUnLinked,
 		}
 	}
-	pack := newReqPack(adapter.setting.Module, module, route, content)
-	for retry := adapter.setting.ReTry; retry > 0; retry-- {
-		resp := adapter.reqInner(pack, 0)
-		switch resp.RespCode {
-		case ERespTimeout:
-			continue
-		default:
-			return resp
-		}
-	}
-	p := PackResp{
-		PackReq:  pack,
-		RespTime: "",
-		RespCode: ERespTimeout,
+	pack := adapter.newReq(module, route, content)
+	resp, _ := adapter.doReq(context.Background(), pack, 0)
+	return resp
+}
+
+// ReqCtx 带上下文的请求
+// ctx 被取消时立即返回 ctx.Err()；ctx 带有截止时间时以截止时间代替 TimeOut
+func (adapter *coreAdapter) ReqCtx(ctx context.Context, module, route string, content []byte) (PackResp, error) {
+	if !adapter.isLinked.Load() {
+		return PackResp{
+			RespCode: ERespUnLinked,
+		}, nil
 	}
-	p.Content = nil
-	return p
+	pack := adapter.newReq(module, route, content)
+	return adapter.doReq(ctx, pack, 0)
+}
+
+// ReqAsync 异步请求，不阻塞调用方
+func (adapter *coreAdapter) ReqAsync(module, route string, content []byte) *ReqFuture {
+	return adapter.ReqAsyncCtx(context.Background(), module, route, content)
+}
+
+// ReqAsyncCtx 带上下文的异步请求，ctx 结束或 ReqFuture.Cancel 时放弃请求
+func (adapter *coreAdapter) ReqAsyncCtx(ctx context.Context, module, route string, content []byte) *ReqFuture {
+	ctx, cancel := context.WithCancel(ctx)
+	future := newReqFuture(cancel)
+	go func() {
+		resp, _ := adapter.ReqCtx(ctx, module, route, content)
+		future.complete(resp)
+	}()
+	return future
 }
 
 // ReqWithTimeout 带超时的请求
 func (adapter *coreAdapter) ReqWithTimeout(module, route string, content []byte, timeout int) PackResp {
-	if !adapter.isLinked {
+	if !adapter.isLinked.Load() {
 		return PackResp{
 			RespCode: ERespUnLinked,
 		}
 	}
-	pack := newReqPack(adapter.setting.Module, module, route, content)
-	for retry := adapter.setting.ReTry; retry >= 0; retry-- {
-		resp := adapter.reqInner(pack, timeout)
-		switch resp.RespCode {
-		case ERespTimeout:
-			continue
-		default:
-			return resp
-		}
-	}
-	p := PackResp{
-		PackReq:  pack,
-		RespTime: "",
-		RespCode: ERespTimeout,
+	pack := adapter.newReq(module, route, content)
+	resp, _ := adapter.doReq(context.Background(), pack, timeout)
+	return resp
+}
+
+// Handle 注册路由处理器
+func (adapter *coreAdapter) Handle(route string, handler IReqHandler) {
+	isServe := adapter.isServeReq()
+	adapter.router.Handle(route, handler)
+	// 连接阶段未订阅请求主题时，首次注册路由补充订阅
+	if !isServe && adapter.isLinked.Load() {
+		adapter.subscribe("Req")
 	}
-	p.Content = nil
-	return p
+}
+
+// isServeReq 是否需要订阅请求主题
+func (adapter *coreAdapter) isServeReq() bool {
+	return !adapter.setting.IsDisableSysRoute || adapter.adapterCallback.OnReqRec != nil || adapter.router.Len() > 0
+}
+
+// HandleFunc 注册路由处理函数
+func (adapter *coreAdapter) HandleFunc(route string, f ReqHandler) {
+	adapter.Handle(route, f)
+}
+
+// HandleCtx 注册可感知取消的路由处理函数
+func (adapter *coreAdapter) HandleCtx(route string, f CtxReqHandler) {
+	adapter.Handle(route, f)
 }
 
 // SendRetainNotice 发送Retain消息
@@ -167,6 +249,26 @@ func (adapter *coreAdapter) CleanRetainNotice(route string) error {
 func (adapter *coreAdapter) SendNotice(route string, content []byte) error {
 	return adapter.sendNoticeInner(route, false, content)
 }
+
+// linkTopic 内部订阅的主题类型与处理函数
+type linkTopic struct {
+	pType EPType
+	f     func(IPack)
+}
+
+// linkSubscriber 内部组件（如日志收集器）订阅主题，重连后自动恢复
+type linkSubscriber interface {
+	subscribeOnLink(topic string, pType EPType, f func(IPack))
+}
+
+// subscribeOnLink 订阅并记录主题，每次连接成功时与其他主题一起重新订阅
+func (adapter *coreAdapter) subscribeOnLink(topic string, pType EPType, f func(IPack)) {
+	adapter.mu.Lock()
+	adapter.linkTopics[topic] = linkTopic{pType: pType, f: f}
+	adapter.mu.Unlock()
+	adapter.engineCallback.OnSubscribe(topic, pType, f)
+}
+
 func (adapter *coreAdapter) SubscribeNotice(route string, isRetain bool) {
 	if isRetain {
 		topic := BuildRetainNoticeTopic(adapter.setting.PreFix, route)
@@ -174,7 +276,7 @@ func (adapter *coreAdapter) SubscribeNotice(route string, isRetain bool) {
 		adapter.retainNoticeTopics[topic] = topic
 		adapter.mu.Unlock()
 		adapter.engineCallback.OnSubscribe(topic, EPTypeNotice, func(pack IPack) {
-			adapter.retainNoticeChan <- *pack.(*PackNotice)
+			adapter.pushRetainNotice(*pack.(*PackNotice))
 		})
 	} else {
 		topic := BuildNoticeTopic(adapter.setting.PreFix, route)
@@ -182,25 +284,34 @@ func (adapter *coreAdapter) SubscribeNotice(route string, isRetain bool) {
 		adapter.noticeTopics[topic] = topic
 		adapter.mu.Unlock()
 		adapter.engineCallback.OnSubscribe(topic, EPTypeNotice, func(pack IPack) {
-			adapter.noticeChan <- *pack.(*PackNotice)
+			adapter.pushNotice(*pack.(*PackNotice))
 		})
 	}
 
 }
 
+func (adapter *coreAdapter) Trace(content string) {
+	adapter.log(1, ELogLevelTrace, content, nil, nil)
+}
+
 func (adapter *coreAdapter) Debug(content string) {
-	pack := newLogPack(adapter.setting.Module, ELogLevelDebug, content)
-	adapter.sendLog(pack)
+	adapter.log(1, ELogLevelDebug, content, nil, nil)
+}
+
+func (adapter *coreAdapter) Info(content string) {
+	adapter.log(1, ELogLevelInfo, content, nil, nil)
 }
 
 func (adapter *coreAdapter) Warn(content string) {
-	pack := newLogPack(adapter.setting.Module, ELogLevelWarning, content)
-	adapter.sendLog(pack)
+	adapter.log(1, ELogLevelWarning, content, nil, nil)
 }
 
 func (adapter *coreAdapter) Err(content string, err error) {
-	pack := newLogPack(adapter.setting.Module, ELogLevelError, content+" "+err.Error())
-	adapter.sendLog(pack)
+	adapter.log(1, ELogLevelError, content, err, nil)
+}
+
+func (adapter *coreAdapter) Log(level ELogLevel, content string, keyValues ...any) {
+	adapter.log(1, level, content, nil, keyValues)
 }
 
 func (adapter *coreAdapter) Publish(topic string, isRetain bool, pack IPack) error {
@@ -213,74 +324,133 @@ func (adapter *coreAdapter) PublishRaw(topic string, isRetain bool, data []byte)
 }
 
 // Inner func #########################################################################################
-func (adapter *coreAdapter) reqInner(pack PackReq, timeout int) PackResp {
-	tsp := adapter.setting.TimeOut
-	if timeout > 0 {
-		tsp = time.Duration(timeout) * time.Millisecond
+func (adapter *coreAdapter) reqInner(ctx context.Context, pack PackReq, timeout int) PackResp {
+	return adapter.interceptReq(pack, func(p PackReq) PackResp {
+		return adapter.reqInvoke(ctx, p, timeout)
+	})
+}
+
+// reqInvoke 发送请求并等待响应
+func (adapter *coreAdapter) reqInvoke(ctx context.Context, pack PackReq, timeout int) PackResp {
+	tsp := adapter.timeoutOf(timeout)
+	if ctx.Err() != nil {
+		return PackResp{
+			RespCode: ERespTimeout,
+		}
 	}
 
-	pre := adapter.setting.PreFix
-	to := pre + pack.To
-	topic := BuildReqTopic(pre, to)
+	topic := adapter.reqTopic(pack.To)
 
 	// 先创建响应通道并注册，再发送请求，避免响应在注册前到达
-	respChan := make(chan PackResp)
+	// 通道带1个缓冲，保证 onRespRec 在等待方已退出时不会阻塞
+	respChan := make(chan PackResp, 1)
 	adapter.mu.Lock()
 	adapter.respDict[pack.Id] = respChan
+	closing := adapter.closing
 	adapter.mu.Unlock()
+	defer adapter.removeResp(pack.Id)
 
 	// 注册完成后再发送请求
-	now := time.Now().Format("15:04:05.000")
-	fmt.Printf("[%s][Core-reqInner] SENDING: ID=%d To=%s Route=%s\n", now, pack.Id, pack.To, pack.Route)
+	adapter.tracer.debug(ETraceCore, "send request", "id", pack.Id, "to", pack.To, "route", pack.Route)
 	e := adapter.engineCallback.OnPublish(topic, false, &pack)
 	if e != nil {
-		// 发送失败，清理已注册的通道
-		adapter.mu.Lock()
-		delete(adapter.respDict, pack.Id)
-		adapter.mu.Unlock()
 		return newRespPack(pack, ERespError, ([]byte)(e.Error()))
 	}
+	atomic.AddUint64(&adapter.stats.reqSent, 1)
 
+	// ctx 带截止时间时，以截止时间代替超时设置
+	var timeoutChan <-chan time.Time
+	if _, ok := ctx.Deadline(); !ok {
+		timer := time.NewTimer(tsp)
+		defer timer.Stop()
+		timeoutChan = timer.C
+	}
 	select {
 	case resp := <-respChan:
-		adapter.mu.Lock()
-		delete(adapter.respDict, pack.Id)
-		adapter.mu.Unlock()
+		// 截止时间后才到达的响应与 ctx 到期竞争，一律按超时处理
+		if deadline, ok := ctx.Deadline(); ok && !time.Now().Before(deadline) {
+			return PackResp{
+				RespCode: ERespTimeout,
+			}
+		}
 		return resp
-	case <-time.After(tsp):
-		adapter.mu.Lock()
-		delete(adapter.respDict, pack.Id)
-		adapter.mu.Unlock()
-		now2 := time.Now().Format("15:04:05.000")
-		fmt.Printf("[%s][Core-reqInner] TIMEOUT: ID=%d To=%s waited full %v\n", now2, pack.Id, pack.To, tsp)
+	case <-closing:
+		return newRespPack(pack, ERespUnLinked, []byte("adapter stopped"))
+	case <-ctx.Done():
+		adapter.tracer.debug(ETraceCore, "request canceled", "id", pack.Id, "to", pack.To, "err", ctx.Err())
 		return PackResp{
 			RespCode: ERespTimeout,
 		}
+	case <-timeoutChan:
+		adapter.tracer.debug(ETraceCore, "request timeout", "id", pack.Id, "to", pack.To, "timeout", tsp)
+		atomic.AddUint64(&adapter.stats.reqTimeout, 1)
+		return PackResp{
+			RespCode: ERespTimeout,
+		}
+	}
+}
+
+// timeoutOf 单次请求的超时时间 timeout 单位毫秒，0为使用 TimeOut
+func (adapter *coreAdapter) timeoutOf(timeout int) time.Duration {
+	if timeout > 0 {
+		return time.Duration(timeout) * time.Millisecond
 	}
+	return adapter.setting.TimeOut
+}
+
+// reqTopic 发往目标模块的请求主题
+func (adapter *coreAdapter) reqTopic(module string) string {
+	pre := adapter.setting.PreFix
+	to := pre + module
+	return BuildReqTopic(pre, to)
+}
+
+// removeResp 清理已注册的响应通道
+func (adapter *coreAdapter) removeResp(id uint64) {
+	adapter.mu.Lock()
+	delete(adapter.respDict, id)
+	adapter.mu.Unlock()
+}
+
+// newTimeoutResp 构造超时响应
+func newTimeoutResp(pack PackReq) PackResp {
+	p := PackResp{
+		PackReq:  pack,
+		RespTime: "",
+		RespCode: ERespTimeout,
+	}
+	p.Content = nil
+	return p
 }
 
 // sendNoticeInner 发消息核心代码
 func (adapter *coreAdapter) sendNoticeInner(route string, isRetain bool, content []byte) error {
 	pack := newNoticePack(adapter.setting.Module, route, content, isRetain)
+	pack.Priority = adapter.routePriority(route)
 	topic := BuildNoticeTopic(adapter.setting.PreFix, route)
 	if isRetain {
 		topic = BuildRetainNoticeTopic(adapter.setting.PreFix, route)
 	}
-	return adapter.engineCallback.OnPublish(topic, isRetain, &pack)
+	return adapter.interceptNotice(pack, func(p PackNotice) error {
+		atomic.AddUint64(&adapter.stats.noticeSent, 1)
+		return adapter.engineCallback.OnPublish(topic, isRetain, &p)
+	})
 }
 
 // onRespRec handle response from respChan
 func (adapter *coreAdapter) onRespRec(pack PackResp) {
-	now := time.Now().Format("15:04:05.000")
-	fmt.Printf("[%s][Core-onRespRec] ID=%d From=%s To=%s\n", now, pack.Id, pack.From, pack.To)
+	atomic.AddUint64(&adapter.stats.respRec, 1)
+	adapter.tracer.debug(ETraceCore, "received response", "id", pack.Id, "from", pack.From, "code", pack.RespCode)
 	adapter.mu.RLock()
 	c, b := adapter.respDict[pack.Id]
 	if b {
-		fmt.Printf("[%s][Core-onRespRec] Found channel for ID=%d, sending...\n", now, pack.Id)
-		c <- pack
-		fmt.Printf("[%s][Core-onRespRec] Sent to channel ID=%d\n", now, pack.Id)
+		select {
+		case c <- pack:
+		default:
+			// 重试时同一ID可能收到多个响应，只保留第一个
+		}
 	} else {
-		fmt.Printf("[%s][Core-onRespRec] NO CHANNEL for ID=%d (may have timed out)\n", now, pack.Id)
+		adapter.tracer.debug(ETraceCore, "no waiter for response, may have timed out", "id", pack.Id)
 	}
 	adapter.mu.RUnlock()
 	if adapter.adapterCallback.OnRespRec != nil {
@@ -290,17 +460,81 @@ func (adapter *coreAdapter) onRespRec(pack PackResp) {
 
 // onReqRec handle request from reqChan
 func (adapter *coreAdapter) onReqRec(pack PackReq) {
-	resp, content := adapter.adapterCallback.OnReqRec(pack)
-	var respPack PackResp
-
-	// content 现在是 []byte 类型，直接使用
-	// 构造响应包时需要特殊处理，因为 newRespPack 期望 any 类型
-	respPack = newRespPack(pack, resp, content)
+	deadline, hasDeadline := pack.DeadlineTime()
+	atomic.AddUint64(&adapter.stats.reqRec, 1)
+	if !adapter.beginHandle() { // 停止中
+		adapter.sendResp(adapter.newReply(pack, ERespServiceUnavailable, []byte("module is stopping")))
+		return
+	}
+	defer adapter.handlers.Done()
+	if hasDeadline && time.Now().After(deadline) { // 请求方已放弃等待，无需处理
+		atomic.AddUint64(&adapter.stats.reqExpired, 1)
+		return
+	}
+	if !adapter.isSysRoute(pack.Route) && !adapter.rateLimiter.allow(pack.From, pack.Route) {
+		atomic.AddUint64(&adapter.stats.reqLimited, 1)
+		adapter.sendResp(adapter.newReply(pack, ERespTooManyRequests, []byte("too many requests")))
+		return
+	}
+	if adapter.idemCache != nil {
+		state, cached := adapter.idemCache.begin(pack)
+		switch state {
+		case idemRunning: // 原请求仍在处理，其响应会满足重试的请求
+			return
+		case idemDone: // 回放缓存的响应
+			atomic.AddUint64(&adapter.stats.reqReplayed, 1)
+			if cached.RespCode != ERespBypass {
+				adapter.sendResp(cached)
+			}
+			return
+		}
+		// 未处理完就返回时删除条目，否则重试的请求会被当作处理中而丢弃
+		defer adapter.idemCache.abort(pack)
+	}
+	parent := context.Background()
+	if hasDeadline {
+		var cancel context.CancelFunc
+		parent, cancel = context.WithDeadline(parent, deadline)
+		defer cancel()
+	}
+	ctx, done := adapter.canceler.start(parent, pack)
+	defer done()
+	if ctx.Err() != nil { // 排队期间已被请求方取消
+		return
+	}
+	release, ok := adapter.routeLimiter.acquire(ctx, pack.Route)
+	if !ok { // 等待路由名额期间请求方已放弃
+		return
+	}
+	defer release()
+	resp, content := adapter.interceptReqRec(pack, func(p PackReq) (EResp, []byte) {
+		return adapter.serveReq(ctx, p)
+	})
+	respPack := adapter.newReply(pack, resp, content)
+	if adapter.idemCache != nil {
+		adapter.idemCache.finish(pack, respPack)
+	}
 
 	if resp == ERespBypass { // 无需回复
 		return
 	}
+	if hasDeadline && !time.Now().Before(deadline) { // 处理到截止时间之后，请求方已按超时返回
+		return
+	}
+	adapter.sendResp(respPack)
+}
+
+// newReply 构造对收到请求的响应，广播请求的响应者是自己
+func (adapter *coreAdapter) newReply(pack PackReq, code EResp, content []byte) PackResp {
+	respPack := newRespPack(pack, code, content)
+	if isBroadcast(pack.To) {
+		respPack.From = adapter.setting.Module
+	}
+	return respPack
+}
 
+// sendResp 发送响应
+func (adapter *coreAdapter) sendResp(respPack PackResp) {
 	// 对于响应，To 字段是目标（原始请求者），From 字段是响应者
 	topic := BuildRespTopic(adapter.setting.PreFix, respPack.To)
 	e := adapter.engineCallback.OnPublish(topic, false, &respPack)
@@ -310,32 +544,21 @@ func (adapter *coreAdapter) onReqRec(pack PackReq) {
 	}
 }
 
-func (adapter *coreAdapter) onReqInner(req PackReq) PackResp {
-	if req.Route == "GetVersion" {
-		var versions []string
-		versions = append(versions, "easy-con:"+getVersion())
-		if adapter.adapterCallback.OnGetVersion != nil {
-			versions = append(versions, adapter.adapterCallback.OnGetVersion()...)
-		}
-		bytes, err := json.Marshal(versions)
-		if err != nil {
-			return newRespPack(req, ERespError, ([]byte)(err.Error()))
-		}
-		respPack := newRespPack(req, ERespSuccess, bytes)
-		return respPack
+// serveReq 路由分发 依次匹配系统路由、注册的路由，未匹配时交给 OnReqRec
+func (adapter *coreAdapter) serveReq(ctx context.Context, pack PackReq) (EResp, []byte) {
+	if code, content, b := adapter.serveSysReq(pack); b {
+		return code, content
 	}
-	if req.Route == "Exit" {
-		if adapter.adapterCallback.OnExiting != nil {
-			adapter.adapterCallback.OnExiting()
+	if h, b := adapter.router.Match(pack.Route); b {
+		if ch, ok := h.(IReqCtxHandler); ok {
+			return ch.ServeReqCtx(ctx, pack)
 		}
-		respPack := newRespPack(req, ERespSuccess, nil)
-		time.AfterFunc(time.Millisecond*100, func() {
-			adapter.Stop()
-		})
-		return respPack
+		return h.ServeReq(pack)
 	}
-	resp, content := adapter.adapterCallback.OnReqRec(req)
-	return newRespPack(req, resp, content)
+	if adapter.adapterCallback.OnReqRec != nil {
+		return adapter.adapterCallback.OnReqRec(pack)
+	}
+	return ERespRouteNotFind, []byte("Route Not Matched")
 }
 func getVersion() string {
 	// 尝试从构建信息中获取版本信息
@@ -361,51 +584,73 @@ func getVersion() string {
 	return "0.0.0"
 }
 func (adapter *coreAdapter) onNoticeRec(pack PackNotice) {
+	atomic.AddUint64(&adapter.stats.noticeRec, 1)
 	if adapter.adapterCallback.OnNoticeRec != nil && pack.From != adapter.setting.Module {
 		adapter.adapterCallback.OnNoticeRec(pack)
 	}
 }
 func (adapter *coreAdapter) onRetainNoticeRec(pack PackNotice) {
+	atomic.AddUint64(&adapter.stats.noticeRec, 1)
 	if adapter.adapterCallback.OnRetainNoticeRec != nil && pack.From != adapter.setting.Module {
 		adapter.adapterCallback.OnRetainNoticeRec(pack)
 	}
 }
 func (adapter *coreAdapter) onLogRec(pack PackLog) {
+	atomic.AddUint64(&adapter.stats.logRec, 1)
 	if adapter.adapterCallback.OnLogRec != nil && pack.From != adapter.setting.Module {
 		adapter.adapterCallback.OnLogRec(pack)
 	}
 }
 func (adapter *coreAdapter) subscribeAtLink() {
-	if adapter.adapterCallback.OnReqRec != nil {
+	if adapter.isServeReq() {
 		adapter.subscribe("Req")
 	}
 	adapter.subscribe("Resp")
 	//如果通知回调不为空，订阅通知主题
 	if adapter.adapterCallback.OnNoticeRec != nil {
-		for topic := range adapter.noticeTopics {
-			adapter.subscribe(topic)
-		}
+		adapter.subscribe("Notice")
 	}
 	if adapter.adapterCallback.OnRetainNoticeRec != nil {
-		for topic := range adapter.retainNoticeTopics {
-			adapter.subscribe(topic)
-		}
+		adapter.subscribe("RetainNotice")
+	}
+	adapter.presence.mu.RLock()
+	isTracking := adapter.presence.tracking
+	adapter.presence.mu.RUnlock()
+	if isTracking {
+		adapter.subscribePresence()
 	}
 	//如果日志回调不为空，订阅日志主题
 	if adapter.adapterCallback.OnLogRec != nil {
 		adapter.subscribe("Log")
 	}
-
+	adapter.mu.RLock()
+	linkTopics := make(map[string]linkTopic, len(adapter.linkTopics))
+	for topic, t := range adapter.linkTopics {
+		linkTopics[topic] = t
+	}
+	adapter.mu.RUnlock()
+	for topic, t := range linkTopics {
+		adapter.engineCallback.OnSubscribe(topic, t.pType, t.f)
+	}
 }
 
 // loop main loop
-func (adapter *coreAdapter) loop() {
+func (adapter *coreAdapter) loop(stop <-chan interface{}) {
+	defer adapter.wg.Done()
+	select {
+	case <-stop: // 启动后立即被停止
+		return
+	default:
+	}
 	adapter.engineCallback.OnLink()
+	select {
+	case <-stop: // 连接成功前被停止
+		return
+	default:
+	}
 	if adapter.adapterCallback.OnLinked != nil {
 		adapter.adapterCallback.OnLinked(adapter)
 	}
-	adapter.wg.Add(1)
-	defer adapter.wg.Done()
 	if adapter.setting.IsSync {
 		ch := make(chan struct{})
 		go func() {
@@ -419,7 +664,21 @@ func (adapter *coreAdapter) loop() {
 			}
 		}()
 		for {
+			// 高优先级通道有消息时先处理
+			select {
+			case msg := <-adapter.reqHighChan:
+				adapter.onReqRec(msg)
+				continue
+			case msg := <-adapter.noticeHighChan:
+				adapter.onNoticeRec(msg)
+				continue
+			default:
+			}
 			select {
+			case msg := <-adapter.reqHighChan:
+				adapter.onReqRec(msg)
+			case msg := <-adapter.noticeHighChan:
+				adapter.onNoticeRec(msg)
 			case msg := <-adapter.noticeChan:
 				adapter.onNoticeRec(msg)
 			case msg := <-adapter.retainNoticeChan:
@@ -428,25 +687,67 @@ func (adapter *coreAdapter) loop() {
 				adapter.onReqRec(msg)
 			case msg := <-adapter.logChan:
 				adapter.onLogRec(msg)
-			case <-adapter.stopChan:
+			case <-stop:
 				ch <- struct{}{}
 				return
 			}
 		}
 	} else {
+		// 设置了协程数的通道交给固定的处理协程，本循环不再读取（nil 通道不会被选中）
+		var noticeChan, noticeHighChan, retainNoticeChan <-chan PackNotice = adapter.noticeChan, adapter.noticeHighChan, adapter.retainNoticeChan
+		var reqChan, reqHighChan <-chan PackReq = adapter.reqChan, adapter.reqHighChan
+		var logChan <-chan PackLog = adapter.logChan
+		quit := make(chan struct{})
+		defer close(quit)
+		if adapter.setting.OrderKey != EOrderNone {
+			bufferSize := cap(adapter.reqChan)
+			reqKey := func(pack PackReq) string { return adapter.orderKey(pack.From, pack.Route) }
+			noticeKey := func(pack PackNotice) string { return adapter.orderKey(pack.From, pack.Route) }
+			startOrdered(adapter.setting.ReqWorkers, bufferSize, reqHighChan, reqChan, reqKey, adapter.onReqRec, quit)
+			startOrdered(adapter.setting.NoticeWorkers, bufferSize, noticeHighChan, noticeChan, noticeKey, adapter.onNoticeRec, quit)
+			startOrdered(adapter.setting.NoticeWorkers, bufferSize, nil, retainNoticeChan, noticeKey, adapter.onRetainNoticeRec, quit)
+			reqChan, reqHighChan, noticeChan, noticeHighChan, retainNoticeChan = nil, nil, nil, nil, nil
+		}
+		if n := adapter.setting.ReqWorkers; n > 0 && reqChan != nil {
+			startWorkers(n, reqHighChan, reqChan, adapter.onReqRec, quit)
+			reqChan, reqHighChan = nil, nil
+		}
+		if n := adapter.setting.NoticeWorkers; n > 0 && noticeChan != nil {
+			startWorkers(n, noticeHighChan, noticeChan, adapter.onNoticeRec, quit)
+			startWorkers(n, nil, retainNoticeChan, adapter.onRetainNoticeRec, quit)
+			noticeChan, noticeHighChan, retainNoticeChan = nil, nil, nil
+		}
+		if n := adapter.setting.LogWorkers; n > 0 {
+			startWorkers(n, nil, logChan, adapter.onLogRec, quit)
+			logChan = nil
+		}
 		for {
+			// 高优先级通道有消息时先分发
 			select {
-			case msg := <-adapter.noticeChan:
+			case msg := <-reqHighChan:
+				go adapter.onReqRec(msg)
+				continue
+			case msg := <-noticeHighChan:
 				go adapter.onNoticeRec(msg)
-			case msg := <-adapter.retainNoticeChan:
+				continue
+			default:
+			}
+			select {
+			case msg := <-reqHighChan:
+				go adapter.onReqRec(msg)
+			case msg := <-noticeHighChan:
+				go adapter.onNoticeRec(msg)
+			case msg := <-noticeChan:
+				go adapter.onNoticeRec(msg)
+			case msg := <-retainNoticeChan:
 				go adapter.onRetainNoticeRec(msg)
-			case msg := <-adapter.reqChan:
+			case msg := <-reqChan:
 				go adapter.onReqRec(msg)
 			case msg := <-adapter.respChan:
 				go adapter.onRespRec(msg)
-			case msg := <-adapter.logChan:
+			case msg := <-logChan:
 				go adapter.onLogRec(msg)
-			case <-adapter.stopChan:
+			case <-stop:
 				return
 			}
 		}
@@ -455,29 +756,77 @@ func (adapter *coreAdapter) loop() {
 }
 
 func (adapter *coreAdapter) link() {
-	go adapter.loop()
+	adapter.resetDrain()
+	stop := make(chan interface{})
+	adapter.mu.Lock()
+	adapter.stopChan = stop
+	adapter.mu.Unlock()
+	// 先登记再启动，保证 Stop 一定等到本轮的 loop 退出
+	adapter.wg.Add(1)
+	go adapter.loop(stop)
 
 }
 
 func (adapter *coreAdapter) sendLog(pack PackLog) {
-	if adapter.setting.LogMode == ELogModeConsole || adapter.setting.LogMode == ELogModeAll {
+	if !isLevelEnabled(pack.Level, adapter.getLogLevel()) {
+		return
+	}
+	adapter.interceptLog(pack, adapter.sendLogInner)
+}
+
+// getLogLevel 当前最低日志级别
+func (adapter *coreAdapter) getLogLevel() ELogLevel {
+	level, _ := adapter.logLevel.Load().(ELogLevel)
+	return level
+}
+
+// sendLogInner 按日志模式输出与上传日志
+func (adapter *coreAdapter) sendLogInner(pack PackLog) {
+	mode := adapter.setting.LogMode
+	if (mode == ELogModeConsole || mode == ELogModeAll) && isLevelEnabled(pack.Level, adapter.setting.ConsoleLogLevel) {
 		printLog(pack)
 	}
-	if adapter.setting.LogMode != ELogModeUpload && adapter.setting.LogMode != ELogModeAll {
+	isFiled := false
+	if adapter.fileLog != nil && isLevelEnabled(pack.Level, adapter.setting.FileLogLevel) {
+		adapter.writeLogFile(pack)
+		isFiled = true
+	}
+	if mode != ELogModeUpload && mode != ELogModeAll {
+		return
+	}
+	if !isLevelEnabled(pack.Level, adapter.setting.UploadLogLevel) {
+		return
+	}
+	// 断线时无法上传，保存到文件，避免丢失
+	if !adapter.isLinked.Load() {
+		if adapter.fileLog != nil && !isFiled {
+			adapter.writeLogFile(pack)
+		}
 		return
 	}
-	topic := adapter.setting.PreFix + LogTopic
+	topic := BuildLogTopic(adapter.setting.PreFix)
 	err := adapter.engineCallback.OnPublish(topic, false, &pack)
 	if err != nil {
-		pack.Content = pack.Content + " " + err.Error()
+		pack.Content = pack.Content + " (upload failed)"
+		pack.Error = joinErr(pack.Error, err)
 		pack.Level = ELogLevelError
+		if adapter.fileLog != nil && !isFiled {
+			adapter.writeLogFile(pack)
+		}
 		printLog(pack)
 		return
 	}
 }
 
+// writeLogFile 写入本地日志文件，失败时写入内部运行日志
+func (adapter *coreAdapter) writeLogFile(pack PackLog) {
+	if err := adapter.fileLog.write(pack); err != nil {
+		adapter.tracer.error("write log file failed", "module", adapter.setting.Module, "err", err)
+	}
+}
+
 func printLog(log PackLog) {
-	fmt.Printf("[%s][%s][%s]: %s \r\n", log.LogTime, log.Level, log.From, log.Content)
+	fmt.Printf("[%s][%s][%s]: %s \r\n", log.LogTime, log.Level, log.From, formatLogContent(log))
 }
 
 func (adapter *coreAdapter) subscribe(kind string) {
@@ -488,67 +837,91 @@ func (adapter *coreAdapter) subscribe(kind string) {
 	case "Req":
 		topic = BuildReqTopic(adapter.setting.PreFix, adapter.setting.Module)
 		adapter.engineCallback.OnSubscribe(topic, EPTypeReq, func(pack IPack) {
-			adapter.reqChan <- *pack.(*PackReq)
+			req := *pack.(*PackReq)
+			if req.PType == EPTypeCancel { // 取消包不排队，立即处理
+				adapter.onCancelRec(req)
+				return
+			}
+			adapter.pushReq(req)
+		})
+		// 广播请求
+		adapter.engineCallback.OnSubscribe(BuildBroadcastReqTopic(adapter.setting.PreFix), EPTypeReq, func(pack IPack) {
+			req := *pack.(*PackReq)
+			if req.From == adapter.setting.Module || !matchModule(req.To, adapter.setting.Module) {
+				return
+			}
+			adapter.pushReq(req)
 		})
 	case "Resp":
 		topic = BuildRespTopic(adapter.setting.PreFix, adapter.setting.Module)
 		adapter.engineCallback.OnSubscribe(topic, EPTypeResp, func(pack IPack) {
-			adapter.respChan <- *pack.(*PackResp)
+			adapter.pushResp(*pack.(*PackResp))
 		})
 	case "Notice":
-		for t := range adapter.noticeTopics {
+		for _, t := range adapter.topicsOf(adapter.noticeTopics) {
 			adapter.engineCallback.OnSubscribe(t, EPTypeNotice, func(pack IPack) {
-				adapter.noticeChan <- *pack.(*PackNotice)
+				adapter.pushNotice(*pack.(*PackNotice))
 			})
 		}
 	case "RetainNotice":
-		for t := range adapter.retainNoticeTopics {
+		for _, t := range adapter.topicsOf(adapter.retainNoticeTopics) {
 			adapter.engineCallback.OnSubscribe(t, EPTypeNotice, func(pack IPack) {
-				adapter.retainNoticeChan <- *pack.(*PackNotice)
+				adapter.pushRetainNotice(*pack.(*PackNotice))
 			})
 		}
 
 	case "Log":
 		topic = BuildLogTopic(adapter.setting.PreFix)
 		adapter.engineCallback.OnSubscribe(topic, EPTypeLog, func(pack IPack) {
-			adapter.logChan <- *pack.(*PackLog)
+			adapter.pushLog(*pack.(*PackLog))
 		})
 	}
 }
 
+// topicsOf 复制已订阅的主题，重连时可能同时有新的订阅
+func (adapter *coreAdapter) topicsOf(topics map[string]interface{}) []string {
+	adapter.mu.RLock()
+	defer adapter.mu.RUnlock()
+	list := make([]string, 0, len(topics))
+	for t := range topics {
+		list = append(list, t)
+	}
+	return list
+}
+
 // onConnected 当连接成功
 func (adapter *coreAdapter) onConnected() {
-	adapter.isLinked = true
+	if status := adapter.Status(); status == EStatusStopping || status == EStatusStopped { // 停止后迟到的连接事件
+		return
+	}
+	adapter.isLinked.Store(true)
 	if adapter.setting.IsWaitLink {
 		adapter.startOnce.Do(func() {
 			close(adapter.startWaitChan)
 		})
 	}
-	if adapter.adapterCallback.OnStatusChanged != nil {
-		adapter.adapterCallback.OnStatusChanged(EStatusLinked)
-	}
+	adapter.setStatus(EStatusLinked)
+	adapter.notifyStatus()
 	err := adapter.SendNotice("Linked", ([]byte)("I am online"))
 	if err != nil {
-		fmt.Printf("Send Notice error %s \r\n", err)
+		adapter.tracer.error("send linked notice failed", "module", adapter.setting.Module, "err", err)
 		return
 	}
 	adapter.subscribeAtLink()
+	adapter.sendPresence(true)
 }
 
 func (adapter *coreAdapter) onReconnecting() {
 
-	if adapter.adapterCallback.OnStatusChanged != nil {
-		adapter.adapterCallback.OnStatusChanged(EStatusConnecting)
-	}
-
+	adapter.setStatus(EStatusConnecting)
+	adapter.notifyStatus()
 }
 
 func (adapter *coreAdapter) onConnectionLost(err error) {
-	adapter.isLinked = false
-	if adapter.adapterCallback.OnStatusChanged != nil {
-		adapter.adapterCallback.OnStatusChanged(EStatusLinkLost)
-	}
-	fmt.Println("Connection lost because", err)
+	adapter.isLinked.Store(false)
+	adapter.setStatus(EStatusLinkLost)
+	adapter.notifyStatus()
+	adapter.tracer.warn("connection lost", "module", adapter.setting.Module, "err", err)
 }
 func (adapter *coreAdapter) GetEngineCallback() EngineCallback {
 	return adapter.engineCallback
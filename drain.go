@@ -0,0 +1,77 @@
+/**
+ * @Author: Joey
+ * @Description: 停止时的排空：拒绝新请求，等待处理中的请求完成，让等待响应的调用立即失败
+ * @Create Date: 2026/10/18 21:00
+ */
+
+package easyCon
+
+import "time"
+
+// defaultDrainTimeout 未设置 DrainTimeout 时等待处理中请求的最长时间
+const defaultDrainTimeout = time.Second * 5
+
+// drainTimeout 排空等待时间，负数为不等待
+func (adapter *coreAdapter) drainTimeout() time.Duration {
+	switch {
+	case adapter.setting.DrainTimeout < 0:
+		return 0
+	case adapter.setting.DrainTimeout == 0:
+		return defaultDrainTimeout
+	default:
+		return adapter.setting.DrainTimeout
+	}
+}
+
+// beginHandle 登记一个开始处理的请求，停止中返回 false
+// 返回 true 时处理完成后须调用 handlers.Done
+func (adapter *coreAdapter) beginHandle() bool {
+	adapter.drainMu.RLock()
+	defer adapter.drainMu.RUnlock()
+	if adapter.stopping {
+		return false
+	}
+	adapter.handlers.Add(1)
+	return true
+}
+
+// drain 不再接受新请求，等待处理中的请求完成或超时，然后让等待响应的调用立即失败
+func (adapter *coreAdapter) drain() {
+	adapter.drainMu.Lock()
+	adapter.stopping = true
+	adapter.drainMu.Unlock()
+
+	if timeout := adapter.drainTimeout(); timeout > 0 {
+		done := make(chan struct{})
+		go func() {
+			adapter.handlers.Wait()
+			close(done)
+		}()
+		timer := time.NewTimer(timeout)
+		select {
+		case <-done:
+		case <-timer.C:
+			adapter.Warn("drain timeout, stop with requests still running")
+		}
+		timer.Stop()
+	}
+
+	adapter.mu.Lock()
+	adapter.isLinked.Store(false)
+	select {
+	case <-adapter.closing:
+	default:
+		close(adapter.closing)
+	}
+	adapter.mu.Unlock()
+}
+
+// resetDrain 重新连接前恢复接受请求
+func (adapter *coreAdapter) resetDrain() {
+	adapter.drainMu.Lock()
+	adapter.stopping = false
+	adapter.drainMu.Unlock()
+	adapter.mu.Lock()
+	adapter.closing = make(chan struct{})
+	adapter.mu.Unlock()
+}
@@ -0,0 +1,84 @@
+/**
+ * @Author: Joey
+ * @Description: 异步请求结果
+ * @Create Date: 2026/10/17 11:05
+ */
+
+package easyCon
+
+import (
+	"context"
+	"sync"
+)
+
+// ReqFuture 异步请求的结果，请求完成且 Then 回调执行完后 Done 通道关闭
+type ReqFuture struct {
+	done        chan struct{}
+	mu          sync.Mutex
+	isCompleted bool
+	resp        PackResp
+	callbacks   []func(PackResp)
+	cancel      context.CancelFunc
+}
+
+func newReqFuture(cancel context.CancelFunc) *ReqFuture {
+	return &ReqFuture{
+		done:   make(chan struct{}),
+		cancel: cancel,
+	}
+}
+
+// Cancel 放弃请求，不再等待响应，未完成时结果为 ERespTimeout
+// 不再需要结果时应调用，否则请求会一直等到超时
+func (f *ReqFuture) Cancel() {
+	f.cancel()
+}
+
+// Done 请求完成时关闭的通道
+func (f *ReqFuture) Done() <-chan struct{} {
+	return f.done
+}
+
+// Wait 阻塞等待请求完成
+func (f *ReqFuture) Wait() PackResp {
+	<-f.done
+	return f.resp
+}
+
+// WaitCtx 等待请求完成或ctx结束，ctx结束时同时取消请求
+func (f *ReqFuture) WaitCtx(ctx context.Context) (PackResp, error) {
+	select {
+	case <-f.done:
+		return f.resp, nil
+	case <-ctx.Done():
+		f.cancel()
+		return PackResp{RespCode: ERespTimeout}, ctx.Err()
+	}
+}
+
+// Then 注册完成回调，若请求已完成则立即回调
+func (f *ReqFuture) Then(callback func(PackResp)) {
+	f.mu.Lock()
+	if f.isCompleted {
+		f.mu.Unlock()
+		callback(f.resp)
+		return
+	}
+	f.callbacks = append(f.callbacks, callback)
+	f.mu.Unlock()
+}
+
+// complete 设置结果，先执行回调再关闭 Done，Wait 返回时已注册的回调都已执行
+func (f *ReqFuture) complete(resp PackResp) {
+	f.mu.Lock()
+	f.resp = resp
+	f.isCompleted = true
+	callbacks := f.callbacks
+	f.callbacks = nil
+	f.mu.Unlock()
+	for _, callback := range callbacks {
+		callback(resp)
+	}
+	f.cancel()
+	close(f.done)
+}
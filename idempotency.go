@@ -0,0 +1,102 @@
+/**
+ * @Author: Joey
+ * @Description: 服务端请求去重缓存，重试的请求直接回放已缓存的响应而不再次执行处理函数
+ * @Create Date: 2026/10/17 16:30
+ */
+
+package easyCon
+
+import (
+	"strconv"
+	"sync"
+	"time"
+)
+
+// idemState 请求在去重缓存中的状态
+type idemState int
+
+const (
+	idemNew     idemState = iota // 首次收到
+	idemRunning                  // 正在处理
+	idemDone                     // 已处理完成
+)
+
+type idemEntry struct {
+	done   bool
+	resp   PackResp
+	expire time.Time
+}
+
+// idemCache 以 (From, Id) 为键的响应缓存
+// 请求 Id 从随机起点开始，请求方重启后不会与重启前的 Id 重复
+type idemCache struct {
+	mu        sync.Mutex
+	ttl       time.Duration
+	entries   map[string]*idemEntry
+	lastSweep time.Time
+}
+
+func newIdemCache(ttl time.Duration) *idemCache {
+	return &idemCache{
+		ttl:       ttl,
+		entries:   make(map[string]*idemEntry),
+		lastSweep: time.Now(),
+	}
+}
+
+func idemKey(pack PackReq) string {
+	return pack.From + "#" + strconv.FormatUint(pack.Id, 10)
+}
+
+// begin 查询请求状态，首次收到时登记为正在处理
+func (cache *idemCache) begin(pack PackReq) (idemState, PackResp) {
+	now := time.Now()
+	key := idemKey(pack)
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	cache.sweep(now)
+	e, b := cache.entries[key]
+	if b && now.Before(e.expire) {
+		if e.done {
+			return idemDone, e.resp
+		}
+		return idemRunning, PackResp{}
+	}
+	cache.entries[key] = &idemEntry{expire: now.Add(cache.ttl)}
+	return idemNew, PackResp{}
+}
+
+// finish 记录处理结果
+func (cache *idemCache) finish(pack PackReq, resp PackResp) {
+	key := idemKey(pack)
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	cache.entries[key] = &idemEntry{
+		done:   true,
+		resp:   resp,
+		expire: time.Now().Add(cache.ttl),
+	}
+}
+
+// abort 放弃处理未完成的请求，删除条目使重试的请求可以重新处理
+func (cache *idemCache) abort(pack PackReq) {
+	key := idemKey(pack)
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	if e, b := cache.entries[key]; b && !e.done {
+		delete(cache.entries, key)
+	}
+}
+
+// sweep 清理过期条目，最多每个TTL周期执行一次
+func (cache *idemCache) sweep(now time.Time) {
+	if now.Sub(cache.lastSweep) < cache.ttl {
+		return
+	}
+	cache.lastSweep = now
+	for key, e := range cache.entries {
+		if now.After(e.expire) {
+			delete(cache.entries, key)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+/**
+ * @Author: Joey
+ * @Description: 拦截器，用于在收发包的过程中统一处理鉴权、审计、统计、校验等逻辑
+ * @Create Date: 2026/10/17 14:30
+ */
+
+package easyCon
+
+// ReqInvoker 发送请求并等待响应
+type ReqInvoker func(pack PackReq) PackResp
+
+// NoticeSender 发送通知
+type NoticeSender func(pack PackNotice) error
+
+// LogSender 发送日志
+type LogSender func(pack PackLog)
+
+// Interceptor 拦截器，各字段均可为空
+// 每个拦截函数可以修改pack后调用next继续处理，也可以不调用next直接返回结果（短路），
+// 在next前后计时即可得到处理耗时
+type Interceptor struct {
+	// OnReqRec 服务端拦截 包裹收到请求后的处理
+	OnReqRec func(pack PackReq, next ReqHandler) (EResp, []byte)
+	// OnReq 客户端拦截 包裹每一次请求的发送与等待
+	OnReq func(pack PackReq, next ReqInvoker) PackResp
+	// OnNotice 客户端拦截 包裹通知的发送
+	OnNotice func(pack PackNotice, next NoticeSender) error
+	// OnLog 客户端拦截 包裹日志的发送
+	OnLog func(pack PackLog, next LogSender)
+}
+
+// Use 添加拦截器，先添加的在外层
+func (adapter *coreAdapter) Use(interceptors ...Interceptor) {
+	adapter.interceptorMu.Lock()
+	defer adapter.interceptorMu.Unlock()
+	// 写时复制，避免正在执行的调用链被修改
+	list := make([]Interceptor, 0, len(adapter.interceptors)+len(interceptors))
+	list = append(list, adapter.interceptors...)
+	list = append(list, interceptors...)
+	adapter.interceptors = list
+}
+
+func (adapter *coreAdapter) getInterceptors() []Interceptor {
+	adapter.interceptorMu.RLock()
+	defer adapter.interceptorMu.RUnlock()
+	return adapter.interceptors
+}
+
+// interceptReqRec 按拦截器链处理收到的请求
+func (adapter *coreAdapter) interceptReqRec(pack PackReq, final ReqHandler) (EResp, []byte) {
+	next := final
+	list := adapter.getInterceptors()
+	for i := len(list) - 1; i >= 0; i-- {
+		f := list[i].OnReqRec
+		if f == nil {
+			continue
+		}
+		inner := next
+		next = func(p PackReq) (EResp, []byte) {
+			return f(p, inner)
+		}
+	}
+	return next(pack)
+}
+
+// interceptReq 按拦截器链发送请求
+func (adapter *coreAdapter) interceptReq(pack PackReq, final ReqInvoker) PackResp {
+	next := final
+	list := adapter.getInterceptors()
+	for i := len(list) - 1; i >= 0; i-- {
+		f := list[i].OnReq
+		if f == nil {
+			continue
+		}
+		inner := next
+		next = func(p PackReq) PackResp {
+			return f(p, inner)
+		}
+	}
+	return next(pack)
+}
+
+// interceptNotice 按拦截器链发送通知
+func (adapter *coreAdapter) interceptNotice(pack PackNotice, final NoticeSender) error {
+	next := final
+	list := adapter.getInterceptors()
+	for i := len(list) - 1; i >= 0; i-- {
+		f := list[i].OnNotice
+		if f == nil {
+			continue
+		}
+		inner := next
+		next = func(p PackNotice) error {
+			return f(p, inner)
+		}
+	}
+	return next(pack)
+}
+
+// interceptLog 按拦截器链发送日志
+func (adapter *coreAdapter) interceptLog(pack PackLog, final LogSender) {
+	next := final
+	list := adapter.getInterceptors()
+	for i := len(list) - 1; i >= 0; i-- {
+		f := list[i].OnLog
+		if f == nil {
+			continue
+		}
+		inner := next
+		next = func(p PackLog) {
+			f(p, inner)
+		}
+	}
+	next(pack)
+}
@@ -7,6 +7,7 @@
 package easyCon
 
 import (
+	"context"
 	"time"
 )
 
@@ -38,18 +39,54 @@ type AdapterCallBack struct {
 	OnGetVersion      func() []string
 	OnLinked          func(adapter IAdapter)
 	OnStatusChanged   StatusChangedHandler
+	OnOverflow        OverflowHandler // 接收通道满导致消息被丢弃
 }
 
 // IAdapter 访问器接口
 type IAdapter interface {
+	// Start 启动，Stop 后可再次启动，已启动时无操作
+	Start()
+
+	// Stop 停止，已停止时无操作
 	Stop()
 
+	// Reset 停止后重新启动
 	Reset()
 
+	// Status 当前状态
+	Status() EStatus
+
 	Req(module, route string, content []byte) PackResp
 
 	ReqWithTimeout(module, route string, content []byte, timeout int) PackResp
 
+	// ReqCtx 带上下文的请求 ctx取消或到期时立即返回
+	ReqCtx(ctx context.Context, module, route string, content []byte) (PackResp, error)
+
+	// ReqAsync 异步请求 立即返回，通过 ReqFuture 获取响应
+	ReqAsync(module, route string, content []byte) *ReqFuture
+
+	// ReqAsyncCtx 带上下文的异步请求 ctx 结束或 ReqFuture.Cancel 时放弃请求
+	ReqAsyncCtx(ctx context.Context, module, route string, content []byte) *ReqFuture
+
+	// ReqMany 同时请求多个模块 收集超时前到达的所有响应
+	ReqMany(modules []string, route string, content []byte, timeout int) MultiResp
+
+	// ReqBroadcast 广播请求 pattern 为 * 或模块名前缀加 * 如 Worker*
+	ReqBroadcast(pattern, route string, content []byte, timeout int) MultiResp
+
+	// Handle 注册路由处理器 route 支持通配 如 user/*
+	Handle(route string, handler IReqHandler)
+
+	// HandleFunc 注册路由处理函数
+	HandleFunc(route string, f ReqHandler)
+
+	// HandleCtx 注册可感知取消的路由处理函数
+	HandleCtx(route string, f CtxReqHandler)
+
+	// Use 添加拦截器
+	Use(interceptors ...Interceptor)
+
 	SendNotice(route string, content []byte) error
 
 	SubscribeNotice(route string, isRetain bool)
@@ -63,6 +100,15 @@ type IAdapter interface {
 	PublishRaw(topic string, isRetain bool, data []byte) error
 
 	GetEngineCallback() EngineCallback
+
+	// Stats 运行统计
+	Stats() Stats
+
+	// OnlineModules 当前在线的模块 首次调用时开始跟踪在线状态
+	OnlineModules() []string
+
+	// WatchPresence 注册模块上线/下线回调
+	WatchPresence(handler PresenceHandler)
 	iLogger
 }
 
@@ -73,12 +119,18 @@ type IProxy interface {
 	Reset()
 }
 type iLogger interface {
+	// Trace 发送跟踪信息
+	Trace(content string)
 	// Debug 发送调试信息
 	Debug(content string)
+	// Info 发送一般信息
+	Info(content string)
 	// Warn 发送警告
 	Warn(content string)
-	// Err 发送错误信息
+	// Err 发送错误信息 err 可为空
 	Err(content string, err error)
+	// Log 发送带结构化键值的日志 keyValues 为 键,值,键,值...
+	Log(level ELogLevel, content string, keyValues ...any)
 }
 
 // MqttSetting 设置
@@ -92,6 +144,13 @@ type MqttSetting struct {
 	MqttKeepAlive    time.Duration // MQTT keepalive间隔，默认30秒
 	MqttPingTimeout  time.Duration // MQTT ping超时，默认10秒
 	MqttWriteTimeout time.Duration // MQTT写入超时，默认无限制
+	// 遗嘱设置，进程崩溃或掉线时由Broker代为发布
+	// WillTopic 与 WillPayload 都为空时默认发布本模块的下线记录（Retain通知 Presence/模块名）
+	IsDisableWill bool        // 不设置遗嘱
+	WillTopic     string      // 遗嘱主题，为空时为 WillPayload 路由对应的通知主题
+	WillPayload   *PackNotice // 遗嘱内容
+	WillQos       byte
+	WillRetain    bool
 }
 
 // CoreSetting 设置
@@ -99,10 +158,19 @@ type CoreSetting struct {
 	Module string
 	// TimeOut 超时时间 毫秒
 	TimeOut time.Duration
-	// ReTry 请求重试次数
+	// ReTry 请求尝试次数，未设置 Retry 时生效：立即重试且只重试超时
 	ReTry int
-	//SaveErrorLog bool
+	// Retry 重试策略，不为空时代替 ReTry
+	Retry   *RetryPolicy
 	LogMode ELogMode
+	// LogFile 本地日志文件，Dir 不为空时在 LogMode 之外同时写入文件，上传失败的日志也会写入
+	LogFile LogFileSetting
+	// LogLevel 最低日志级别，低于该级别的日志不输出，为空时输出全部，可通过 SetLogLevel 系统路由修改
+	LogLevel ELogLevel
+	// ConsoleLogLevel UploadLogLevel FileLogLevel 控制台输出、上传与文件各自的最低日志级别，为空时不额外过滤
+	ConsoleLogLevel ELogLevel
+	UploadLogLevel  ELogLevel
+	FileLogLevel    ELogLevel
 	//PreFix 通用topic前缀 影响log notice
 	PreFix string
 	// ChannelBufferSize 各种消息通道的缓冲区大小
@@ -112,6 +180,47 @@ type CoreSetting struct {
 	IsWaitLink        bool // IsWaitLink 等待连接
 	// IsSync 是否同步
 	IsSync bool
+	// ReqWorkers 异步模式下处理请求的协程数，0为每条消息一个协程
+	ReqWorkers int
+	// NoticeWorkers 异步模式下处理通知（含Retain通知）的协程数，0为每条消息一个协程
+	NoticeWorkers int
+	// LogWorkers 异步模式下处理日志的协程数，0为每条消息一个协程
+	LogWorkers int
+	// OrderKey 异步模式下按分组键保序处理请求与通知：同组串行，不同组并行
+	// 分片数为 ReqWorkers / NoticeWorkers，未设置时为16
+	OrderKey EOrderKey
+	// RoutePriority 路由优先级：本模块发出的请求与通知带上该优先级，收到的请求与通知按包头与该设置中较高者进入高优先级通道
+	RoutePriority map[string]EPriority
+	// RouteConcurrency 各路由同时处理的请求数上限，未列出的路由不限制
+	RouteConcurrency map[string]int
+	// RateLimit 按调用模块与路由限流，超出时回复 ERespTooManyRequests，系统路由不受限
+	// 可通过 SetRateLimit 系统路由修改
+	RateLimit RateLimitSetting
+	// 接收通道满时的溢出策略，默认阻塞
+	ReqOverflow    EOverflowPolicy
+	RespOverflow   EOverflowPolicy
+	NoticeOverflow EOverflowPolicy // 通知与Retain通知
+	LogOverflow    EOverflowPolicy
+	// IdempotentTTL 请求去重缓存时长，0为不启用
+	// 启用后以 (From, Id) 识别重试的请求，直接回放缓存的响应，不再重复执行处理函数
+	IdempotentTTL time.Duration
+	// IsSendCancel 放弃等待请求（超时或ctx取消）时通知响应方取消处理
+	// 响应方需支持 PackTypeCancel，旧版本会因无法解析而记录错误日志
+	IsSendCancel bool
+	// IsDisableSysRoute 不响应系统路由（GetVersion Exit Ping GetStats GetRoutes GetConfig SetLogLevel SetRateLimit）
+	IsDisableSysRoute bool
+	// SysRouteAllow 允许调用受保护系统路由（Exit GetConfig SetLogLevel SetRateLimit）的模块，支持 * 与前缀加 *，为空时全部拒绝
+	SysRouteAllow []string
+	// Logger 内部运行日志，为空时不输出，可用 NewSlogLogger 接入 slog
+	Logger ITraceLogger `json:"-"`
+	// TraceScopes 开启调试跟踪的子系统，跟踪信息以 Debug 级别写入 Logger
+	TraceScopes []ETraceScope
+	// DrainTimeout Stop 时等待处理中请求完成的最长时间，0为5秒，负数为不等待
+	DrainTimeout time.Duration
+	// IsTrackPresence 连接后立即跟踪其他模块的在线状态，否则在首次调用 OnlineModules/WatchPresence 时开始
+	IsTrackPresence bool
+	// IsFastFail 目标模块已知离线时请求立即返回 ERespTargetOffline，开启后自动跟踪在线状态
+	IsFastFail bool
 }
 
 // MqttProxySetting 代理设置
@@ -125,6 +234,8 @@ type MqttProxySetting struct {
 	//ReTry   int
 	TimeOut        time.Duration
 	LogForwardMode ELogForwardMode // 日志转发模式
+	Logger         ITraceLogger    `json:"-"` // 内部运行日志
+	TraceScopes    []ETraceScope   // 开启调试跟踪的子系统
 }
 
 // NewDefaultMqttSetting 快速新建设置 默认3秒延迟 3次重试
@@ -0,0 +1,74 @@
+/**
+ * @Author: Joey
+ * @Description: 适配器生命周期：Stopped -> Connecting -> Linked <-> LinkLost -> Stopping -> Stopped
+ * @Create Date: 2026/10/18 22:00
+ */
+
+package easyCon
+
+// Start 启动，已启动时直接返回，可并发、重复调用
+func (adapter *coreAdapter) Start() {
+	defer adapter.notifyStatus()
+	adapter.lifeMu.Lock()
+	defer adapter.lifeMu.Unlock()
+	adapter.start()
+}
+
+func (adapter *coreAdapter) start() {
+	if adapter.Status() != EStatusStopped {
+		return
+	}
+	adapter.setStatus(EStatusConnecting)
+	adapter.link()
+}
+
+// Status 当前状态
+func (adapter *coreAdapter) Status() EStatus {
+	adapter.statusMu.RLock()
+	defer adapter.statusMu.RUnlock()
+	return adapter.status
+}
+
+// setStatus 切换状态并记录待通知的切换，由 notifyStatus 在释放 lifeMu 后通知
+// Stopping 只能转为 Stopped，Stopped 只能转为 Connecting，停止后迟到的连接事件被忽略
+func (adapter *coreAdapter) setStatus(status EStatus) {
+	adapter.statusMu.Lock()
+	defer adapter.statusMu.Unlock()
+	old := adapter.status
+	if old == status ||
+		(old == EStatusStopping && status != EStatusStopped) ||
+		(old == EStatusStopped && status != EStatusConnecting) {
+		return
+	}
+	adapter.status = status
+	if adapter.adapterCallback.OnStatusChanged != nil {
+		adapter.statusQueue = append(adapter.statusQueue, status)
+	}
+}
+
+// notifyStatus 按顺序发出记录的状态通知，回调中可以调用 Start Stop Reset
+// 已有协程在通知时由其继续发出，保证顺序
+func (adapter *coreAdapter) notifyStatus() {
+	adapter.statusMu.Lock()
+	if adapter.isNotifying {
+		adapter.statusMu.Unlock()
+		return
+	}
+	adapter.isNotifying = true
+	for len(adapter.statusQueue) > 0 {
+		status := adapter.statusQueue[0]
+		adapter.statusQueue = adapter.statusQueue[1:]
+		adapter.statusMu.Unlock()
+		adapter.adapterCallback.OnStatusChanged(status)
+		adapter.statusMu.Lock()
+	}
+	adapter.isNotifying = false
+	adapter.statusMu.Unlock()
+}
+
+// stopSignal 本轮运行的停止信号，Stop 时关闭
+func (adapter *coreAdapter) stopSignal() <-chan interface{} {
+	adapter.mu.RLock()
+	defer adapter.mu.RUnlock()
+	return adapter.stopChan
+}
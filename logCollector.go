@@ -0,0 +1,118 @@
+/**
+ * @Author: Joey
+ * @Description: 日志收集器，订阅各前缀的日志主题保存到本地，通过 QueryLogs 路由查询
+ * @Create Date: 2026/10/18 14:10
+ */
+
+package easyCon
+
+import "time"
+
+// RouteQueryLogs 查询收集的日志，请求为 LogQuery JSON，响应为 LogQueryResult JSON
+const RouteQueryLogs = "QueryLogs"
+
+// LogCollectorSetting 日志收集设置
+type LogCollectorSetting struct {
+	// Dir 存储目录，为空时为 logStore
+	Dir string
+	// PreFixes 收集的主题前缀，为空时只收集模块自身的前缀
+	PreFixes []string
+	// MaxSize 存储总字节数，超过后删除最早的分段，0为1GB
+	MaxSize int64
+	// MaxAge 日志保留时长，超过后删除最早的分段，0为不限制
+	MaxAge time.Duration
+}
+
+// LogCollector 日志收集器
+type LogCollector struct {
+	adapter  IAdapter
+	store    *logStore
+	module   string
+	prefixes []string
+}
+
+// NewMqttLogCollector 创建 MQTT 日志收集器，收集各前缀的日志，并在模块自身的前缀上响应 QueryLogs
+func NewMqttLogCollector(setting MqttSetting, collect LogCollectorSetting, callback AdapterCallBack) (*LogCollector, error) {
+	collector, err := newLogCollector(setting.Module, setting.PreFix, collect)
+	if err != nil {
+		return nil, err
+	}
+	setting.IsWaitLink = false
+	collector.adapter = newMqttAdapterInner(setting, callback)
+	collector.handle()
+	collector.subscribe()
+	return collector, nil
+}
+
+// NewCgoLogCollector 创建 CGO 日志收集器，CGO 只能收到模块自身前缀的日志
+func NewCgoLogCollector(setting CoreSetting, collect LogCollectorSetting, callback AdapterCallBack, onWrite func([]byte) error, localBroker func([]byte) error) (*LogCollector, func([]byte), error) {
+	collector, err := newLogCollector(setting.Module, setting.PreFix, collect)
+	if err != nil {
+		return nil, nil, err
+	}
+	setting.IsWaitLink = false
+	var onRead func([]byte)
+	collector.adapter, onRead = NewCgoAdapterWithBroker(setting, callback, onWrite, localBroker)
+	collector.handle()
+	collector.subscribe()
+	return collector, onRead, nil
+}
+
+// newLogCollector 打开存储
+func newLogCollector(module, prefix string, collect LogCollectorSetting) (*LogCollector, error) {
+	store, err := openLogStore(collect)
+	if err != nil {
+		return nil, err
+	}
+	prefixes := collect.PreFixes
+	if len(prefixes) == 0 {
+		prefixes = []string{prefix}
+	}
+	return &LogCollector{store: store, module: module, prefixes: prefixes}, nil
+}
+
+// subscribe 订阅各前缀的日志主题，重连后由访问器重新订阅
+func (collector *LogCollector) subscribe() {
+	subscriber := collector.adapter.(linkSubscriber)
+	for _, p := range collector.prefixes {
+		p := p
+		subscriber.subscribeOnLink(BuildLogTopic(p), EPTypeLog, func(pack IPack) {
+			collector.save(collector.adapter, collector.module, p, *pack.(*PackLog))
+		})
+	}
+}
+
+// save 保存日志，保存自身的失败日志再失败时不再上报，避免循环
+func (collector *LogCollector) save(adapter IAdapter, module, prefix string, pack PackLog) {
+	err := collector.store.append(prefix, pack)
+	if err != nil && pack.From != module {
+		adapter.Err("log store append failed", err)
+	}
+}
+
+func (collector *LogCollector) handle() {
+	HandleTyped[LogQuery, LogQueryResult](collector.adapter, RouteQueryLogs, func(_ PackReq, q LogQuery) (EResp, LogQueryResult) {
+		result, err := collector.store.query(q)
+		if err != nil {
+			collector.adapter.Err("log store query failed", err)
+			return ERespError, result
+		}
+		return ERespSuccess, result
+	})
+}
+
+// Query 本地查询收集的日志
+func (collector *LogCollector) Query(q LogQuery) (LogQueryResult, error) {
+	return collector.store.query(q)
+}
+
+// Adapter 收集器使用的访问器
+func (collector *LogCollector) Adapter() IAdapter {
+	return collector.adapter
+}
+
+// Stop 停止访问器并关闭存储
+func (collector *LogCollector) Stop() {
+	collector.adapter.Stop()
+	_ = collector.store.close()
+}
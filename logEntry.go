@@ -0,0 +1,100 @@
+/**
+ * @Author: Joey
+ * @Description: 日志包的组装：错误、调用位置、结构化键值
+ * @Create Date: 2026/10/18 09:20
+ */
+
+package easyCon
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// badKey 落单的值使用的键，与 slog 一致
+const badKey = "!BADKEY"
+
+// log 组装日志包并发送 skip 为调用位置向上跳过的层数：0 为调用 log 处，Trace/Debug/Info/Warn/Err/Log 传 1 取其调用方
+func (adapter *coreAdapter) log(skip int, level ELogLevel, content string, err error, keyValues []any) {
+	if !isLevelEnabled(level, adapter.getLogLevel()) {
+		return
+	}
+	pack := newLogPack(adapter.setting.Module, level, content)
+	if err != nil {
+		pack.Error = err.Error()
+	}
+	pack.Caller = callerOf(skip + 2)
+	pack.Fields = fieldsOf(keyValues)
+	adapter.sendLog(pack)
+}
+
+// callerOf 调用位置 目录/文件:行号
+func callerOf(skip int) string {
+	_, file, line, ok := runtime.Caller(skip)
+	if !ok {
+		return ""
+	}
+	dir, name := filepath.Split(file)
+	return filepath.Base(dir) + "/" + name + ":" + strconv.Itoa(line)
+}
+
+// fieldsOf 键,值,键,值... 转为键值表，error 转为文本
+func fieldsOf(keyValues []any) map[string]any {
+	if len(keyValues) == 0 {
+		return nil
+	}
+	fields := make(map[string]any, (len(keyValues)+1)/2)
+	for i := 0; i < len(keyValues); i += 2 {
+		key, ok := keyValues[i].(string)
+		if !ok || i+1 == len(keyValues) {
+			fields[badKey] = logValue(keyValues[i])
+			i--
+			continue
+		}
+		fields[key] = logValue(keyValues[i+1])
+	}
+	return fields
+}
+
+func logValue(v any) any {
+	if err, ok := v.(error); ok {
+		return err.Error()
+	}
+	return v
+}
+
+// joinErr 追加错误信息
+func joinErr(text string, err error) string {
+	if text == "" {
+		return err.Error()
+	}
+	return text + "; " + err.Error()
+}
+
+// formatLogContent 控制台输出的正文：内容 键=值... err=错误 (调用位置)
+func formatLogContent(log PackLog) string {
+	if log.Error == "" && log.Caller == "" && len(log.Fields) == 0 {
+		return log.Content
+	}
+	var sb strings.Builder
+	sb.WriteString(log.Content)
+	keys := make([]string, 0, len(log.Fields))
+	for k := range log.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(&sb, " %s=%v", k, log.Fields[k])
+	}
+	if log.Error != "" {
+		sb.WriteString(" err=" + log.Error)
+	}
+	if log.Caller != "" {
+		sb.WriteString(" (" + log.Caller + ")")
+	}
+	return sb.String()
+}
@@ -0,0 +1,178 @@
+/**
+ * @Author: Joey
+ * @Description: 本地日志文件，按大小与时间切分，按数量与时长清理
+ * @Create Date: 2026/10/18 10:30
+ */
+
+package easyCon
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultLogFileDir     = "log"
+	defaultLogFileMaxSize = 10 << 20
+	logFileTimeLayout     = "20060102-150405.000"
+)
+
+// LogFileSetting 本地日志文件设置
+type LogFileSetting struct {
+	// Dir 日志目录，不为空时在 LogMode 之外同时写入文件；LogMode 为 FILE 且为空时为 log
+	Dir string
+	// MaxSize 单个文件最大字节数，超过后切分，0为10MB
+	MaxSize int64
+	// MaxAge 单个文件最长写入时长，超过后切分，0为不按时间切分
+	MaxAge time.Duration
+	// MaxBackups 保留的切分文件数，0为不限制
+	MaxBackups int
+	// MaxKeepAge 切分文件保留时长，0为不限制
+	MaxKeepAge time.Duration
+}
+
+// fileLogger 写入 Dir/模块名.log，切分后改名为 模块名-时间.log
+type fileLogger struct {
+	mu       sync.Mutex
+	setting  LogFileSetting
+	name     string
+	file     *os.File
+	size     int64
+	openTime time.Time
+}
+
+func newFileLogger(module string, setting LogFileSetting) *fileLogger {
+	if setting.Dir == "" {
+		setting.Dir = defaultLogFileDir
+	}
+	if setting.MaxSize <= 0 {
+		setting.MaxSize = defaultLogFileMaxSize
+	}
+	name := strings.NewReplacer("/", "_", "\\", "_", "*", "_").Replace(module)
+	return &fileLogger{setting: setting, name: name}
+}
+
+func (l *fileLogger) path() string {
+	return filepath.Join(l.setting.Dir, l.name+".log")
+}
+
+// write 写入一行日志，需要时先切分
+func (l *fileLogger) write(pack PackLog) error {
+	line := []byte("[" + pack.LogTime + "][" + string(pack.Level) + "][" + pack.From + "]: " + formatLogContent(pack) + "\n")
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file != nil && l.isFull(len(line)) {
+		if err := l.rotate(); err != nil {
+			return err
+		}
+	}
+	if l.file == nil {
+		if err := l.open(); err != nil {
+			return err
+		}
+	}
+	n, err := l.file.Write(line)
+	l.size += int64(n)
+	return err
+}
+
+func (l *fileLogger) isFull(n int) bool {
+	if l.size == 0 { // 空文件不切分
+		return false
+	}
+	if l.size+int64(n) > l.setting.MaxSize {
+		return true
+	}
+	return l.setting.MaxAge > 0 && time.Since(l.openTime) >= l.setting.MaxAge
+}
+
+// open 打开当前文件，沿用已有内容
+func (l *fileLogger) open() error {
+	if err := os.MkdirAll(l.setting.Dir, 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(l.path(), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	l.file = f
+	l.size = info.Size()
+	l.openTime = time.Now()
+	// 已有文件按修改时间计算写入时长，避免频繁重启时永不切分
+	if l.size > 0 && info.ModTime().Before(l.openTime) {
+		l.openTime = info.ModTime()
+	}
+	return nil
+}
+
+// rotate 关闭并改名当前文件，然后清理过期的切分文件
+func (l *fileLogger) rotate() error {
+	_ = l.file.Close()
+	l.file = nil
+	if err := os.Rename(l.path(), l.backupPath()); err != nil {
+		return err
+	}
+	l.clean()
+	return nil
+}
+
+// backupPath 切分文件名，同一毫秒内已有切分文件时顺延一毫秒，避免覆盖且仍可按名称排序
+func (l *fileLogger) backupPath() string {
+	for t := time.Now(); ; t = t.Add(time.Millisecond) {
+		backup := filepath.Join(l.setting.Dir, l.name+"-"+t.Format(logFileTimeLayout)+".log")
+		if _, err := os.Stat(backup); err != nil {
+			return backup
+		}
+	}
+}
+
+// clean 删除超出数量或时长的切分文件，先删最旧的
+func (l *fileLogger) clean() {
+	if l.setting.MaxBackups <= 0 && l.setting.MaxKeepAge <= 0 {
+		return
+	}
+	matches, _ := filepath.Glob(filepath.Join(l.setting.Dir, l.name+"-*.log"))
+	// 排除名称以 模块名- 开头的其他模块的文件
+	backups := matches[:0]
+	for _, match := range matches {
+		stamp := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(match), l.name+"-"), ".log")
+		if _, err := time.Parse(logFileTimeLayout, stamp); err == nil {
+			backups = append(backups, match)
+		}
+	}
+	// 文件名中的时间可按字典序排序
+	sort.Strings(backups)
+	for i, backup := range backups {
+		remain := len(backups) - i
+		if l.setting.MaxBackups > 0 && remain > l.setting.MaxBackups {
+			_ = os.Remove(backup)
+			continue
+		}
+		if l.setting.MaxKeepAge <= 0 {
+			continue
+		}
+		info, err := os.Stat(backup)
+		if err == nil && time.Since(info.ModTime()) > l.setting.MaxKeepAge {
+			_ = os.Remove(backup)
+		}
+	}
+}
+
+// close 关闭文件，下次写入时重新打开
+func (l *fileLogger) close() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file != nil {
+		_ = l.file.Close()
+		l.file = nil
+	}
+}
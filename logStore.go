@@ -0,0 +1,488 @@
+/**
+ * @Author: Joey
+ * @Description: 日志收集器的本地存储，分段追加写入，按总大小与时长整段清理，内存中按模块、级别、时间建立索引
+ * @Create Date: 2026/10/18 13:30
+ */
+
+package easyCon
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultLogStoreDir  = "logStore"
+	defaultLogStoreSize = 1 << 30
+	logStoreSegments    = 8 // 分段大小与时长为总大小与保留时长的几分之一，清理时整段删除
+	logSegmentPrefix    = "logs-"
+	logSegmentExt       = ".jsonl"
+	defaultLogQueryRows = 100
+	maxLogQueryRows     = 1000
+)
+
+// LogRecord 存储的一条日志
+type LogRecord struct {
+	Seq      uint64
+	PreFix   string // 收到日志的主题前缀
+	RecvTime string
+	Id       uint64
+	From     string
+	Level    ELogLevel
+	LogTime  string
+	Content  string
+	Error    string         `json:",omitempty"`
+	Caller   string         `json:",omitempty"`
+	Fields   map[string]any `json:",omitempty"`
+}
+
+// LogQuery QueryLogs 的请求，条件为空时不过滤
+type LogQuery struct {
+	// Module 来源模块，支持 * 与前缀加 * 如 Worker*
+	Module string
+	PreFix string
+	// Level 最低级别
+	Level ELogLevel
+	// Start End 日志时间范围 格式同 LogTime，可只写前面部分如 2026-10-18，含 Start 不含 End
+	Start string
+	End   string
+	// Text 内容、错误或结构化字段中包含的文本
+	Text string
+	// Limit 最多返回条数，返回最新的几条，0为100，最大1000
+	Limit int
+}
+
+// LogQueryResult QueryLogs 的响应，按日志时间排序
+type LogQueryResult struct {
+	Logs   []LogRecord
+	IsMore bool // 还有更早的日志未返回
+}
+
+// logSegment 一个分段文件，文件名为段内第一条日志的序号
+type logSegment struct {
+	file      *os.File
+	firstSeq  uint64
+	size      int64
+	count     int       // 段内日志条数
+	openTime  time.Time // 开始写入的时间，用于按时长切分
+	writeTime time.Time // 最后写入的时间，用于按时长清理
+}
+
+// logIndex 一条日志在文件中的位置与索引字段
+type logIndex struct {
+	seg     *logSegment
+	offset  int64
+	size    int
+	module  string
+	prefix  string
+	level   ELogLevel
+	logTime string
+}
+
+// logStore 分段追加写入的日志文件，重启时扫描文件重建索引
+// 索引中记录日志的位置，entries[0] 的位置为 base，删除最早的分段后 base 增加
+type logStore struct {
+	mu       sync.RWMutex
+	dir      string
+	maxSize  int64
+	segSize  int64
+	segAge   time.Duration
+	maxAge   time.Duration
+	segments []*logSegment // 按序号排序，最后一个为写入中的分段
+	nextSeq  uint64
+	base     int
+	entries  []logIndex
+	byModule map[string][]int
+	byLevel  map[ELogLevel][]int
+	byTime   []int // 按日志时间排序
+}
+
+func openLogStore(setting LogCollectorSetting) (*logStore, error) {
+	dir := setting.Dir
+	if dir == "" {
+		dir = defaultLogStoreDir
+	}
+	maxSize := setting.MaxSize
+	if maxSize <= 0 {
+		maxSize = defaultLogStoreSize
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	store := &logStore{
+		dir:      dir,
+		maxSize:  maxSize,
+		segSize:  maxSize / logStoreSegments,
+		segAge:   setting.MaxAge / logStoreSegments,
+		maxAge:   setting.MaxAge,
+		nextSeq:  1,
+		byModule: make(map[string][]int),
+		byLevel:  make(map[ELogLevel][]int),
+	}
+	if err := store.load(); err != nil {
+		store.closeFiles()
+		return nil, err
+	}
+	if len(store.segments) == 0 {
+		if err := store.roll(); err != nil {
+			return nil, err
+		}
+	}
+	store.retain()
+	return store, nil
+}
+
+// load 按序号扫描各分段重建索引，序号取文件名与日志中最大的，保证重启后只增不减
+func (s *logStore) load() error {
+	matches, err := filepath.Glob(filepath.Join(s.dir, logSegmentPrefix+"*"+logSegmentExt))
+	if err != nil {
+		return err
+	}
+	// 文件名中的序号补齐位数，可按字典序排序
+	sort.Strings(matches)
+	for i, path := range matches {
+		name := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(path), logSegmentPrefix), logSegmentExt)
+		firstSeq, err := strconv.ParseUint(name, 10, 64)
+		if err != nil {
+			continue
+		}
+		if err = s.loadSegment(path, firstSeq, i == len(matches)-1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadSegment 扫描一个分段，截掉写入中的分段异常退出时未写完的最后一行
+func (s *logStore) loadSegment(path string, firstSeq uint64, isLast bool) error {
+	f, err := os.OpenFile(path, os.O_RDWR, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	seg := &logSegment{file: f, firstSeq: firstSeq, openTime: info.ModTime(), writeTime: info.ModTime()}
+	s.segments = append(s.segments, seg)
+	if firstSeq >= s.nextSeq {
+		s.nextSeq = firstSeq
+	}
+	reader := bufio.NewReader(f)
+	var offset int64
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		var record LogRecord
+		if json.Unmarshal(line, &record) == nil {
+			if seg.count == 0 {
+				if t, err := time.ParseInLocation("2006-01-02 15:04:05.000", record.RecvTime, time.Local); err == nil {
+					seg.openTime = t
+				}
+			}
+			s.index(seg, record, offset, len(line))
+			if record.Seq >= s.nextSeq {
+				s.nextSeq = record.Seq + 1
+			}
+		}
+		offset += int64(len(line))
+	}
+	seg.size = offset
+	if !isLast {
+		return nil
+	}
+	return f.Truncate(offset)
+}
+
+func (s *logStore) index(seg *logSegment, record LogRecord, offset int64, size int) {
+	i := s.base + len(s.entries)
+	seg.count++
+	s.entries = append(s.entries, logIndex{
+		seg:     seg,
+		offset:  offset,
+		size:    size,
+		module:  record.From,
+		prefix:  record.PreFix,
+		level:   record.Level,
+		logTime: record.LogTime,
+	})
+	s.byModule[record.From] = append(s.byModule[record.From], i)
+	s.byLevel[record.Level] = append(s.byLevel[record.Level], i)
+	// 日志基本按时间到达，通常直接追加
+	n := len(s.byTime)
+	if n == 0 || s.entry(s.byTime[n-1]).logTime <= record.LogTime {
+		s.byTime = append(s.byTime, i)
+		return
+	}
+	at := sort.Search(n, func(k int) bool { return s.entry(s.byTime[k]).logTime > record.LogTime })
+	s.byTime = append(s.byTime, 0)
+	copy(s.byTime[at+1:], s.byTime[at:])
+	s.byTime[at] = i
+}
+
+// entry 位置 i 的日志索引
+func (s *logStore) entry(i int) logIndex {
+	return s.entries[i-s.base]
+}
+
+// append 写入一条日志，当前分段写满或写入过久时先切换到新分段
+func (s *logStore) append(prefix string, pack PackLog) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	seg := s.segments[len(s.segments)-1]
+	if seg.count > 0 && (seg.size >= s.segSize || (s.segAge > 0 && time.Since(seg.openTime) >= s.segAge)) {
+		if err := s.roll(); err != nil {
+			return err
+		}
+		s.retain()
+		seg = s.segments[len(s.segments)-1]
+	}
+	record := LogRecord{
+		Seq:      s.nextSeq,
+		PreFix:   prefix,
+		RecvTime: getNowStr(),
+		Id:       pack.Id,
+		From:     pack.From,
+		Level:    pack.Level,
+		LogTime:  pack.LogTime,
+		Content:  pack.Content,
+		Error:    pack.Error,
+		Caller:   pack.Caller,
+		Fields:   pack.Fields,
+	}
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+	if _, err = seg.file.WriteAt(line, seg.size); err != nil {
+		return err
+	}
+	s.nextSeq++
+	if seg.count == 0 {
+		seg.openTime = time.Now()
+	}
+	seg.writeTime = time.Now()
+	s.index(seg, record, seg.size, len(line))
+	seg.size += int64(len(line))
+	return nil
+}
+
+// roll 新建分段，文件名为下一条日志的序号
+func (s *logStore) roll() error {
+	path := filepath.Join(s.dir, fmt.Sprintf("%s%020d%s", logSegmentPrefix, s.nextSeq, logSegmentExt))
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	s.segments = append(s.segments, &logSegment{file: f, firstSeq: s.nextSeq, openTime: now, writeTime: now})
+	return nil
+}
+
+// retain 从最早的分段开始删除，直到总大小不超过上限且没有超过保留时长的分段，写入中的分段不删除
+func (s *logStore) retain() {
+	var total int64
+	for _, seg := range s.segments {
+		total += seg.size
+	}
+	n := 0
+	for n < len(s.segments)-1 {
+		seg := s.segments[n]
+		isExpired := s.maxAge > 0 && time.Since(seg.writeTime) > s.maxAge
+		if total <= s.maxSize && !isExpired {
+			break
+		}
+		total -= seg.size
+		_ = seg.file.Close()
+		_ = os.Remove(seg.file.Name())
+		s.drop(seg.count)
+		n++
+	}
+	s.segments = s.segments[n:]
+}
+
+// drop 从索引中删除最早的 count 条日志
+func (s *logStore) drop(count int) {
+	if count == 0 {
+		return
+	}
+	s.entries = s.entries[count:]
+	s.base += count
+	// 按模块、级别的列表按位置递增
+	for module, l := range s.byModule {
+		if l = l[sort.SearchInts(l, s.base):]; len(l) == 0 {
+			delete(s.byModule, module)
+		} else {
+			s.byModule[module] = l
+		}
+	}
+	for level, l := range s.byLevel {
+		if l = l[sort.SearchInts(l, s.base):]; len(l) == 0 {
+			delete(s.byLevel, level)
+		} else {
+			s.byLevel[level] = l
+		}
+	}
+	byTime := s.byTime[:0]
+	for _, i := range s.byTime {
+		if i >= s.base {
+			byTime = append(byTime, i)
+		}
+	}
+	s.byTime = byTime
+}
+
+// query 先用索引缩小范围，再逐条过滤，从最新的开始取
+func (s *logStore) query(q LogQuery) (LogQueryResult, error) {
+	limit := q.Limit
+	if limit <= 0 {
+		limit = defaultLogQueryRows
+	}
+	if limit > maxLogQueryRows {
+		limit = maxLogQueryRows
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var result LogQueryResult
+	candidates := s.candidates(q)
+	for k := len(candidates) - 1; k >= 0; k-- {
+		e := s.entry(candidates[k])
+		if !s.isMatch(e, q) {
+			continue
+		}
+		record, err := s.read(e)
+		if err != nil {
+			return result, err
+		}
+		if q.Text != "" && !isTextMatch(record, q.Text) {
+			continue
+		}
+		if len(result.Logs) == limit {
+			result.IsMore = true
+			break
+		}
+		result.Logs = append(result.Logs, record)
+	}
+	// 倒序取出，翻转为按时间排序
+	for i, j := 0, len(result.Logs)-1; i < j; i, j = i+1, j-1 {
+		result.Logs[i], result.Logs[j] = result.Logs[j], result.Logs[i]
+	}
+	return result, nil
+}
+
+// candidates 按时间排序的候选日志
+func (s *logStore) candidates(q LogQuery) []int {
+	var list []int
+	switch {
+	case q.Module != "" && !isBroadcast(q.Module):
+		list = append(list, s.byModule[q.Module]...)
+	case q.Module != "" && q.Module != BroadcastModule:
+		for module, l := range s.byModule {
+			if matchModule(q.Module, module) {
+				list = append(list, l...)
+			}
+		}
+	case q.Level != "":
+		for level, l := range s.byLevel {
+			if isLevelEnabled(level, q.Level) {
+				list = append(list, l...)
+			}
+		}
+	default:
+		from, to := 0, len(s.byTime)
+		if q.Start != "" {
+			from = sort.Search(to, func(k int) bool { return s.entry(s.byTime[k]).logTime >= q.Start })
+		}
+		if q.End != "" {
+			to = sort.Search(to, func(k int) bool { return s.entry(s.byTime[k]).logTime >= q.End })
+		}
+		if from >= to {
+			return nil
+		}
+		return s.byTime[from:to]
+	}
+	sort.Slice(list, func(a, b int) bool {
+		ea, eb := s.entry(list[a]), s.entry(list[b])
+		if ea.logTime != eb.logTime {
+			return ea.logTime < eb.logTime
+		}
+		return list[a] < list[b]
+	})
+	return list
+}
+
+func (s *logStore) isMatch(e logIndex, q LogQuery) bool {
+	if q.Module != "" && !isModuleMatch(q.Module, e.module) {
+		return false
+	}
+	if q.PreFix != "" && q.PreFix != e.prefix {
+		return false
+	}
+	if q.Level != "" && !isLevelEnabled(e.level, q.Level) {
+		return false
+	}
+	if q.Start != "" && e.logTime < q.Start {
+		return false
+	}
+	return q.End == "" || e.logTime < q.End
+}
+
+func isModuleMatch(pattern, module string) bool {
+	if isBroadcast(pattern) {
+		return matchModule(pattern, module)
+	}
+	return pattern == module
+}
+
+func isTextMatch(record LogRecord, text string) bool {
+	if strings.Contains(record.Content, text) || strings.Contains(record.Error, text) {
+		return true
+	}
+	if len(record.Fields) == 0 {
+		return false
+	}
+	fields, _ := json.Marshal(record.Fields)
+	return strings.Contains(string(fields), text)
+}
+
+func (s *logStore) read(e logIndex) (LogRecord, error) {
+	var record LogRecord
+	buf := make([]byte, e.size)
+	if _, err := e.seg.file.ReadAt(buf, e.offset); err != nil {
+		return record, err
+	}
+	err := json.Unmarshal(buf, &record)
+	return record, err
+}
+
+func (s *logStore) close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closeFiles()
+}
+
+func (s *logStore) closeFiles() error {
+	var err error
+	for _, seg := range s.segments {
+		if e := seg.file.Close(); e != nil {
+			err = e
+		}
+	}
+	return err
+}
@@ -22,7 +22,11 @@ func NewMqttMonitor(setting MqttSetting, callback AdapterCallBack) IAdapter {
 		}
 		if callback.OnReqRec != nil {
 			adapter.GetEngineCallback().OnSubscribe(BuildReqTopic(setting.PreFix, "#"), EPTypeReq, func(pack IPack) {
-				callback.OnReqRec(*pack.(*PackReq))
+				reqPack := *pack.(*PackReq)
+				if reqPack.PType == EPTypeCancel { // 取消包不是请求
+					return
+				}
+				callback.OnReqRec(reqPack)
 			})
 		}
 		if callback.OnRespRec != nil {
@@ -57,6 +61,9 @@ func NewCGoMonitorWithBroker(setting CoreSetting, callback AdapterCallBack, onWr
 				if strings.HasPrefix(reqPack.From, setting.Module) { //来自自己的就不需要再触发了
 					return
 				}
+				if reqPack.PType == EPTypeCancel { // 取消包不是请求
+					return
+				}
 
 				callback.OnReqRec(reqPack)
 			})
@@ -9,14 +9,23 @@ package easyCon
 import (
 	"fmt"
 	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"sync"
 	"time"
 )
 
 type mqttAdapter struct {
 	*coreAdapter
-	client  mqtt.Client
-	setting MqttSetting
-	options *mqtt.ClientOptions
+	clientMu sync.RWMutex // 每轮启动时替换 client，发布与订阅可能同时进行
+	client   mqtt.Client
+	setting  MqttSetting
+	options  *mqtt.ClientOptions
+}
+
+// getClient 当前轮的 client，未启动时为空
+func (adapter *mqttAdapter) getClient() mqtt.Client {
+	adapter.clientMu.RLock()
+	defer adapter.clientMu.RUnlock()
+	return adapter.client
 }
 
 func NewMqttAdapter(setting MqttSetting, callback AdapterCallBack) IAdapter {
@@ -63,16 +72,63 @@ func newMqttAdapterInner(setting MqttSetting, callback AdapterCallBack) *mqttAda
 	}
 
 	o.OnReconnecting = func(client mqtt.Client, options *mqtt.ClientOptions) {
+		setWill(options, setting)
 		adapter.onReconnecting()
 
 	}
 	adapter.options = o
 	adapter.coreAdapter = newCoreAdapter(setting.CoreSetting, ecb, callback)
+	adapter.Start()
 	//等待连接成功。内部会根据配置阻塞
 	adapter.coreAdapter.waitLink()
 	return adapter
 }
 
+// setWill 每次连接前设置 MQTT 遗嘱，默认为本模块连接时生成的下线记录
+func setWill(o *mqtt.ClientOptions, setting MqttSetting) {
+	if topic, payload, qos, retain, ok := buildWill(setting); ok {
+		o.SetBinaryWill(topic, payload, qos, retain)
+	}
+}
+
+// buildWill 生成 MQTT 遗嘱，ok 为 false 时不设置遗嘱
+func buildWill(setting MqttSetting) (topic string, payload []byte, qos byte, retain bool, ok bool) {
+	if setting.IsDisableWill {
+		return
+	}
+	pack := setting.WillPayload
+	topic = setting.WillTopic
+	qos = setting.WillQos
+	retain = setting.WillRetain
+	if pack == nil && topic == "" {
+		offline := newPresenceNotice(setting.Module, false)
+		pack = &offline
+		retain = true
+	}
+	if pack == nil {
+		return
+	}
+	will := *pack
+	if will.PType == "" {
+		will.PType = EPTypeNotice
+	}
+	if will.From == "" {
+		will.From = setting.Module
+	}
+	if topic == "" {
+		topic = BuildNoticeTopic(setting.PreFix, will.Route)
+		if will.Retain {
+			topic = BuildRetainNoticeTopic(setting.PreFix, will.Route)
+		}
+	}
+	payload, err := will.Raw()
+	if err != nil {
+		return
+	}
+	ok = true
+	return
+}
+
 func (adapter *mqttAdapter) onStop() (isOk bool, err error) {
 	defer func() {
 		e := recover()
@@ -81,7 +137,14 @@ func (adapter *mqttAdapter) onStop() (isOk bool, err error) {
 			err = fmt.Errorf("mqtt client stop error %v", e)
 		}
 	}()
-	adapter.client.Disconnect(100)
+	// 断开前发送队列中的包，最长等待排空时间
+	quiesce := uint(100)
+	if drain := adapter.drainTimeout(); drain > time.Duration(quiesce)*time.Millisecond {
+		quiesce = uint(drain.Milliseconds())
+	}
+	if client := adapter.getClient(); client != nil {
+		client.Disconnect(quiesce)
+	}
 	isOk = true
 	return
 }
@@ -92,7 +155,11 @@ func (adapter *mqttAdapter) onPublish(topic string, isRetain bool, pack IPack) e
 		return err
 	}
 
-	token := adapter.client.Publish(topic, 0, isRetain, raw)
+	client := adapter.getClient()
+	if client == nil {
+		return fmt.Errorf("client is nil")
+	}
+	token := client.Publish(topic, 0, isRetain, raw)
 	// 异步发送：不等待确认，避免阻塞
 	_ = token
 	return nil
@@ -100,11 +167,12 @@ func (adapter *mqttAdapter) onPublish(topic string, isRetain bool, pack IPack) e
 
 // PublishRaw publishes raw byte data (zero-copy)
 func (adapter *mqttAdapter) PublishRaw(topic string, isRetain bool, data []byte) error {
-	if adapter.client == nil {
+	client := adapter.getClient()
+	if client == nil {
 		return fmt.Errorf("client is nil")
 	}
 
-	token := adapter.client.Publish(topic, 0, isRetain, data)
+	token := client.Publish(topic, 0, isRetain, data)
 	// 异步发送：不等待确认，避免阻塞
 	// if token.Wait() && token.Error() != nil {
 	// 	return token.Error()
@@ -115,7 +183,11 @@ func (adapter *mqttAdapter) PublishRaw(topic string, isRetain bool, data []byte)
 
 // SubscribeInternalNotice Subscribe InternalNotice if route is "", route will be # and will subscribe all
 func (adapter *mqttAdapter) onSubscribe(topic string, _ EPType, f func(pack IPack)) {
-	adapter.client.Subscribe(topic, 0, func(_ mqtt.Client, message mqtt.Message) {
+	client := adapter.getClient()
+	if client == nil { // 连接后重新订阅
+		return
+	}
+	client.Subscribe(topic, 0, func(_ mqtt.Client, message mqtt.Message) {
 		pack, err := UnmarshalPack(message.Payload())
 		if err != nil {
 			adapter.Err("Deserialize error", err)
@@ -131,16 +203,26 @@ func (adapter *mqttAdapter) onLink() {
 	//	suffix = "." + strconv.FormatInt(time.Now().UnixNano(), 10)
 	//}
 	adapter.options.SetClientID(adapter.setting.PreFix + adapter.setting.Module + suffix)
-	adapter.client = mqtt.NewClient(adapter.options)
-ReLink:
-	token := adapter.client.Connect()
-	if token.Wait() && token.Error() != nil {
+	setWill(adapter.options, adapter.setting)
+	client := mqtt.NewClient(adapter.options)
+	adapter.clientMu.Lock()
+	adapter.client = client
+	adapter.clientMu.Unlock()
+	stop := adapter.stopSignal()
+	for {
+		token := client.Connect()
+		if !token.Wait() || token.Error() == nil {
+			return
+		}
 		// 使用配置的重试延迟，默认为1秒
 		retryDelay := adapter.setting.ConnectRetryDelay
 		if retryDelay <= 0 {
 			retryDelay = time.Second
 		}
-		time.Sleep(retryDelay)
-		goto ReLink
+		select {
+		case <-time.After(retryDelay):
+		case <-stop: // 连接成功前被停止
+			return
+		}
 	}
 }
@@ -13,6 +13,7 @@ func NewMqttProxy(settingA, settingB MqttProxySetting, proxyNotice, ProxyRetainN
 		proxyNotice:       proxyNotice,
 		proxyRetainNotice: ProxyRetainNotice,
 		proxyLog:          ProxyLog,
+		tracer:            newTracer(settingA.Logger, settingA.TraceScopes),
 	}
 
 	sa := NewDefaultMqttSetting("Proxy", settingA.Addr)
@@ -23,6 +24,8 @@ func NewMqttProxy(settingA, settingB MqttProxySetting, proxyNotice, ProxyRetainN
 	sa.TimeOut = settingA.TimeOut
 	sa.PWD = settingA.PWD
 	sa.UID = settingA.UID
+	sa.Logger = settingA.Logger
+	sa.TraceScopes = settingA.TraceScopes
 
 	cba := AdapterCallBack{
 		OnReqRec:          p.onReqA,
@@ -37,6 +40,8 @@ func NewMqttProxy(settingA, settingB MqttProxySetting, proxyNotice, ProxyRetainN
 	sb.TimeOut = settingB.TimeOut
 	sb.PWD = settingB.PWD
 	sb.UID = settingB.UID
+	sb.Logger = settingB.Logger
+	sb.TraceScopes = settingB.TraceScopes
 
 	cbb := AdapterCallBack{
 		OnReqRec:          p.onReqB,
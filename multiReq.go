@@ -0,0 +1,147 @@
+/**
+ * @Author: Joey
+ * @Description: 多模块请求（scatter-gather）与广播请求
+ * @Create Date: 2026/10/17 18:00
+ */
+
+package easyCon
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// MultiResp 多模块请求结果，key为模块名
+type MultiResp map[string]PackResp
+
+// Succeeded 响应成功的模块
+func (m MultiResp) Succeeded() []string {
+	var modules []string
+	for module, resp := range m {
+		if resp.RespCode == ERespSuccess {
+			modules = append(modules, module)
+		}
+	}
+	return modules
+}
+
+// Failed 响应失败或超时的模块
+func (m MultiResp) Failed() []string {
+	var modules []string
+	for module, resp := range m {
+		if resp.RespCode != ERespSuccess {
+			modules = append(modules, module)
+		}
+	}
+	return modules
+}
+
+// isBroadcast 目标是否为广播模式 以*结尾
+func isBroadcast(to string) bool {
+	return strings.HasSuffix(to, BroadcastModule)
+}
+
+// matchModule 模块名是否匹配广播模式，如 * 匹配全部，Worker* 匹配 Worker 开头的模块
+func matchModule(pattern, module string) bool {
+	return strings.HasPrefix(module, strings.TrimSuffix(pattern, BroadcastModule))
+}
+
+// ReqMany 同时向多个模块发送请求，收集超时前到达的所有响应
+// 未响应的模块在结果中为 ERespTimeout，timeout 单位毫秒，0为使用 TimeOut
+func (adapter *coreAdapter) ReqMany(modules []string, route string, content []byte, timeout int) MultiResp {
+	result := make(MultiResp, len(modules))
+	if !adapter.isLinked.Load() {
+		for _, module := range modules {
+			result[module] = PackResp{RespCode: ERespUnLinked}
+		}
+		return result
+	}
+	var lock sync.Mutex
+	var wg sync.WaitGroup
+	deadline := time.Now().Add(adapter.timeoutOf(timeout))
+	for _, module := range modules {
+		pack := adapter.newReq(module, route, content)
+		if adapter.isKnownOffline(module) {
+			resp := adapter.newOfflineResp(pack)
+			lock.Lock()
+			result[module] = resp
+			lock.Unlock()
+			continue
+		}
+		pack.SetDeadline(deadline)
+		wg.Add(1)
+		go func(module string) {
+			defer wg.Done()
+			resp := adapter.reqInner(context.Background(), pack, timeout)
+			if resp.RespCode == ERespTimeout {
+				resp = newTimeoutResp(pack)
+			}
+			lock.Lock()
+			result[module] = resp
+			lock.Unlock()
+		}(module)
+	}
+	wg.Wait()
+	return result
+}
+
+// ReqBroadcast 向匹配 pattern 的所有模块广播一个请求，收集超时前到达的所有响应
+// pattern 为 * 时广播给所有模块，Worker* 只有 Worker 开头的模块会响应
+// 广播请求只发送一次，timeout 单位毫秒，0为使用 TimeOut
+func (adapter *coreAdapter) ReqBroadcast(pattern, route string, content []byte, timeout int) MultiResp {
+	result := make(MultiResp)
+	if !adapter.isLinked.Load() {
+		return result
+	}
+	if !isBroadcast(pattern) {
+		pattern += BroadcastModule
+	}
+	tsp := adapter.timeoutOf(timeout)
+	pack := adapter.newReq(pattern, route, content)
+	pack.SetDeadline(time.Now().Add(tsp))
+	// 与单个请求一样经过拦截器链，拦截器收到的响应为汇总：有响应时为 ERespSuccess，否则为 ERespTimeout
+	adapter.interceptReq(pack, func(p PackReq) PackResp {
+		return adapter.broadcastInvoke(p, tsp, result)
+	})
+	return result
+}
+
+// broadcastInvoke 发送广播请求，把超时前到达的响应写入 result
+func (adapter *coreAdapter) broadcastInvoke(pack PackReq, tsp time.Duration, result MultiResp) PackResp {
+	respChan := make(chan PackResp, cap(adapter.respChan))
+	adapter.mu.Lock()
+	adapter.respDict[pack.Id] = respChan
+	closing := adapter.closing
+	adapter.mu.Unlock()
+	defer adapter.removeResp(pack.Id)
+
+	topic := BuildBroadcastReqTopic(adapter.setting.PreFix)
+	atomic.AddUint64(&adapter.stats.reqSent, 1)
+	if e := adapter.engineCallback.OnPublish(topic, false, &pack); e != nil {
+		adapter.Err("broadcast send error", e)
+		return newRespPack(pack, ERespError, []byte(e.Error()))
+	}
+	timer := time.NewTimer(tsp)
+	defer timer.Stop()
+	for {
+		select {
+		case resp := <-respChan:
+			result[resp.From] = resp
+		case <-closing:
+			return broadcastSummary(pack, result)
+		case <-timer.C:
+			return broadcastSummary(pack, result)
+		}
+	}
+}
+
+// broadcastSummary 广播请求的汇总响应
+func broadcastSummary(pack PackReq, result MultiResp) PackResp {
+	if len(result) == 0 {
+		return newTimeoutResp(pack)
+	}
+	return newRespPack(pack, ERespSuccess, nil)
+}
@@ -0,0 +1,123 @@
+/**
+ * @Author: Joey
+ * @Description: 接收通道满时的溢出策略
+ * @Create Date: 2026/10/18 17:20
+ */
+
+package easyCon
+
+import "sync/atomic"
+
+// EOverflowPolicy 通道溢出策略
+type EOverflowPolicy string
+
+const (
+	// EOverflowBlock 阻塞等待通道空出（默认），会阻塞订阅回调
+	EOverflowBlock EOverflowPolicy = ""
+	// EOverflowDropNewest 丢弃新到的消息
+	EOverflowDropNewest EOverflowPolicy = "DROP_NEWEST"
+	// EOverflowDropOldest 丢弃通道中最早的消息，为新消息腾出位置
+	EOverflowDropOldest EOverflowPolicy = "DROP_OLDEST"
+	// EOverflowReject 丢弃新到的消息，请求回复 ERespServiceUnavailable，其他消息同 EOverflowDropNewest
+	EOverflowReject EOverflowPolicy = "REJECT"
+)
+
+// OverflowHandler 溢出回调，kind 为 Req Resp Notice RetainNotice Log，pack 为被丢弃的消息
+// 在订阅回调中同步调用，不要阻塞
+type OverflowHandler func(kind string, policy EOverflowPolicy, pack IPack)
+
+// offer 按策略向通道写入消息，返回被丢弃的消息
+func offer[T any](ch chan T, msg T, policy EOverflowPolicy) (dropped []T) {
+	switch policy {
+	case EOverflowDropNewest, EOverflowReject:
+		select {
+		case ch <- msg:
+		default:
+			dropped = append(dropped, msg)
+		}
+		return
+	case EOverflowDropOldest:
+		// 通道已满时取出最早的消息再写入，其他协程同时写入抢占空位时继续取出，不阻塞
+		for {
+			select {
+			case ch <- msg:
+				return
+			default:
+			}
+			select {
+			case old := <-ch:
+				dropped = append(dropped, old)
+			default:
+			}
+		}
+	default:
+		ch <- msg
+		return
+	}
+}
+
+// overflow 记录丢弃并触发回调
+func (adapter *coreAdapter) overflow(kind string, counter *uint64, policy EOverflowPolicy, pack IPack) {
+	atomic.AddUint64(counter, 1)
+	if adapter.adapterCallback.OnOverflow != nil {
+		adapter.adapterCallback.OnOverflow(kind, policy, pack)
+	}
+}
+
+// pushReq 请求入队
+func (adapter *coreAdapter) pushReq(req PackReq) {
+	policy := adapter.setting.ReqOverflow
+	ch := adapter.reqChan
+	if adapter.isHigh(req.Priority, req.Route) {
+		ch = adapter.reqHighChan
+	}
+	for _, dropped := range offer(ch, req, policy) {
+		dropped := dropped
+		adapter.overflow("Req", &adapter.stats.reqDropped, policy, &dropped)
+		if policy != EOverflowReject {
+			continue
+		}
+		// 不在订阅回调中等待发布完成
+		go adapter.sendResp(adapter.newReply(dropped, ERespServiceUnavailable, []byte("request queue is full")))
+	}
+}
+
+// pushResp 响应入队
+func (adapter *coreAdapter) pushResp(resp PackResp) {
+	policy := adapter.setting.RespOverflow
+	for _, dropped := range offer(adapter.respChan, resp, policy) {
+		dropped := dropped
+		adapter.overflow("Resp", &adapter.stats.respDropped, policy, &dropped)
+	}
+}
+
+// pushNotice 通知入队
+func (adapter *coreAdapter) pushNotice(notice PackNotice) {
+	policy := adapter.setting.NoticeOverflow
+	ch := adapter.noticeChan
+	if adapter.isHigh(notice.Priority, notice.Route) {
+		ch = adapter.noticeHighChan
+	}
+	for _, dropped := range offer(ch, notice, policy) {
+		dropped := dropped
+		adapter.overflow("Notice", &adapter.stats.noticeDropped, policy, &dropped)
+	}
+}
+
+// pushRetainNotice Retain通知入队
+func (adapter *coreAdapter) pushRetainNotice(notice PackNotice) {
+	policy := adapter.setting.NoticeOverflow
+	for _, dropped := range offer(adapter.retainNoticeChan, notice, policy) {
+		dropped := dropped
+		adapter.overflow("RetainNotice", &adapter.stats.noticeDropped, policy, &dropped)
+	}
+}
+
+// pushLog 日志入队
+func (adapter *coreAdapter) pushLog(log PackLog) {
+	policy := adapter.setting.LogOverflow
+	for _, dropped := range offer(adapter.logChan, log, policy) {
+		dropped := dropped
+		adapter.overflow("Log", &adapter.stats.logDropped, policy, &dropped)
+	}
+}
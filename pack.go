@@ -8,6 +8,7 @@ package easyCon
 
 import (
 	"encoding/json"
+	"time"
 )
 
 // IPack 数据包接口
@@ -33,25 +34,52 @@ func (p *packBase) IsRetain() bool  { return false }
 // PackReq 请求数据包
 type PackReq struct {
 	packBase
-	From    string
-	ReqTime string
-	To      string
-	Route   string
-	Content []byte
+	From     string
+	ReqTime  string
+	To       string
+	Route    string
+	Priority EPriority
+	Content  []byte
+	// deadline 请求方放弃等待的时间（本机时间），零值为不限制
+	// 包头中传递剩余毫秒数，收到时按本机时间重建，不受主机间时钟偏差影响
+	deadline time.Time
 }
 
 func (p *PackReq) Target() string { return p.To }
 
+// DeadlineTime 请求截止时间
+func (p *PackReq) DeadlineTime() (time.Time, bool) {
+	return p.deadline, !p.deadline.IsZero()
+}
+
+// SetDeadline 设置请求截止时间
+func (p *PackReq) SetDeadline(deadline time.Time) {
+	p.deadline = deadline
+}
+
+// budget 发送时距截止时间的剩余毫秒数，0为不限制，已过期时为-1
+func (p *PackReq) budget() int64 {
+	if p.deadline.IsZero() {
+		return 0
+	}
+	if remain := time.Until(p.deadline).Milliseconds(); remain > 0 {
+		return remain
+	}
+	return -1
+}
+
 func (p *PackReq) Raw() ([]byte, error) {
 	header := PackReqHeader{
 		PackBaseHeader: PackBaseHeader{
 			PType: p.PType,
 			Id:    p.Id,
 		},
-		From:    p.From,
-		ReqTime: p.ReqTime,
-		To:      p.To,
-		Route:   p.Route,
+		From:     p.From,
+		ReqTime:  p.ReqTime,
+		To:       p.To,
+		Route:    p.Route,
+		Budget:   p.budget(),
+		Priority: p.Priority,
 	}
 	headerJson, err := json.Marshal(header)
 	if err != nil {
@@ -68,6 +96,9 @@ func (p *PackReq) Raw() ([]byte, error) {
 
 	buf := make([]byte, totalLen)
 	buf[0] = PackTypeReq
+	if p.PType == EPTypeCancel {
+		buf[0] = PackTypeCancel
+	}
 	buf[1] = byte(headLen >> 8)
 	buf[2] = byte(headLen)
 	copy(buf[3:], headerJson)
@@ -130,6 +161,12 @@ type PackLog struct {
 	Level   ELogLevel
 	LogTime string
 	Content string
+	// Error 错误信息，Err 时填写
+	Error string
+	// Caller 调用位置 目录/文件:行号
+	Caller string
+	// Fields 结构化键值
+	Fields map[string]any
 }
 
 func (p *PackLog) Raw() ([]byte, error) {
@@ -141,6 +178,9 @@ func (p *PackLog) Raw() ([]byte, error) {
 		From:    p.From,
 		Level:   string(p.Level),
 		LogTime: p.LogTime,
+		Error:   p.Error,
+		Caller:  p.Caller,
+		Fields:  p.Fields,
 	}
 	headerJson, err := json.Marshal(header)
 	if err != nil {
@@ -163,10 +203,11 @@ func (p *PackLog) Raw() ([]byte, error) {
 // PackNotice 通知数据包
 type PackNotice struct {
 	packBase
-	From    string
-	Route   string
-	Retain  bool
-	Content []byte
+	From     string
+	Route    string
+	Retain   bool
+	Priority EPriority
+	Content  []byte
 }
 
 func (p *PackNotice) IsRetain() bool { return p.Retain }
@@ -177,9 +218,10 @@ func (p *PackNotice) Raw() ([]byte, error) {
 			PType: p.PType,
 			Id:    p.Id,
 		},
-		From:   p.From,
-		Route:  p.Route,
-		Retain: p.Retain,
+		From:     p.From,
+		Route:    p.Route,
+		Retain:   p.Retain,
+		Priority: p.Priority,
 	}
 	headerJson, err := json.Marshal(header)
 	if err != nil {
@@ -0,0 +1,156 @@
+/**
+ * @Author: Joey
+ * @Description: 模块在线状态，每个模块以Retain通知发布自己的在线记录，需要时订阅所有模块的记录
+ * @Create Date: 2026/10/18 15:00
+ */
+
+package easyCon
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// PresenceRoute 在线记录的通知路由前缀，完整路由为 Presence/模块名
+const PresenceRoute = "Presence"
+
+// PresenceInfo 模块在线记录
+type PresenceInfo struct {
+	Module string
+	Online bool
+	Time   string
+}
+
+// PresenceHandler 模块上线/下线回调
+type PresenceHandler func(module string, online bool)
+
+// presenceTracker 记录其他模块的在线状态
+type presenceTracker struct {
+	mu       sync.RWMutex
+	tracking bool
+	modules  map[string]bool
+	watchers []PresenceHandler
+}
+
+func newPresenceTracker() *presenceTracker {
+	return &presenceTracker{
+		modules: make(map[string]bool),
+	}
+}
+
+// update 更新模块状态，状态变化时通知观察者
+func (tracker *presenceTracker) update(module string, online bool) {
+	tracker.mu.Lock()
+	last, known := tracker.modules[module]
+	tracker.modules[module] = online
+	watchers := tracker.watchers
+	tracker.mu.Unlock()
+	if known && last == online {
+		return
+	}
+	for _, w := range watchers {
+		w(module, online)
+	}
+}
+
+// state 模块状态 known 为 false 表示没有收到过该模块的记录
+func (tracker *presenceTracker) state(module string) (online bool, known bool) {
+	tracker.mu.RLock()
+	defer tracker.mu.RUnlock()
+	online, known = tracker.modules[module]
+	return
+}
+
+func (tracker *presenceTracker) online() []string {
+	tracker.mu.RLock()
+	defer tracker.mu.RUnlock()
+	var modules []string
+	for module, online := range tracker.modules {
+		if online {
+			modules = append(modules, module)
+		}
+	}
+	sort.Strings(modules)
+	return modules
+}
+
+// newPresenceNotice 构造在线记录通知
+func newPresenceNotice(module string, online bool) PackNotice {
+	content, _ := json.Marshal(PresenceInfo{
+		Module: module,
+		Online: online,
+		Time:   getNowStr(),
+	})
+	return newNoticePack(module, PresenceRoute+"/"+module, content, true)
+}
+
+// sendPresence 发布自己的在线记录
+func (adapter *coreAdapter) sendPresence(online bool) {
+	pack := newPresenceNotice(adapter.setting.Module, online)
+	topic := BuildRetainNoticeTopic(adapter.setting.PreFix, pack.Route)
+	err := adapter.interceptNotice(pack, func(p PackNotice) error {
+		return adapter.engineCallback.OnPublish(topic, true, &p)
+	})
+	if err != nil {
+		adapter.Err("Presence send error", err)
+	}
+}
+
+// trackPresence 开始跟踪其他模块的在线状态
+func (adapter *coreAdapter) trackPresence() {
+	adapter.presence.mu.Lock()
+	isTracking := adapter.presence.tracking
+	adapter.presence.tracking = true
+	adapter.presence.mu.Unlock()
+	if !isTracking && adapter.isLinked.Load() {
+		adapter.subscribePresence()
+	}
+}
+
+// subscribePresence 订阅所有模块的在线记录
+func (adapter *coreAdapter) subscribePresence() {
+	topic := BuildRetainNoticeTopic(adapter.setting.PreFix, PresenceRoute+"/#")
+	adapter.engineCallback.OnSubscribe(topic, EPTypeNotice, func(pack IPack) {
+		notice := pack.(*PackNotice)
+		if notice.From == adapter.setting.Module || !strings.HasPrefix(notice.Route, PresenceRoute+"/") {
+			return
+		}
+		var info PresenceInfo
+		if len(notice.Content) == 0 || json.Unmarshal(notice.Content, &info) != nil {
+			return
+		}
+		adapter.presence.update(info.Module, info.Online)
+	})
+}
+
+// OnlineModules 当前在线的模块
+func (adapter *coreAdapter) OnlineModules() []string {
+	adapter.trackPresence()
+	return adapter.presence.online()
+}
+
+// WatchPresence 注册模块上线/下线回调
+func (adapter *coreAdapter) WatchPresence(handler PresenceHandler) {
+	adapter.presence.mu.Lock()
+	adapter.presence.watchers = append(adapter.presence.watchers, handler)
+	adapter.presence.mu.Unlock()
+	adapter.trackPresence()
+}
+
+// isKnownOffline 目标模块是否已知离线，只在启用 IsFastFail 时生效
+func (adapter *coreAdapter) isKnownOffline(module string) bool {
+	if !adapter.setting.IsFastFail {
+		return false
+	}
+	online, known := adapter.presence.state(module)
+	return known && !online
+}
+
+// newOfflineResp 构造目标离线响应
+func (adapter *coreAdapter) newOfflineResp(pack PackReq) PackResp {
+	atomic.AddUint64(&adapter.stats.reqFastFail, 1)
+	return newRespPack(pack, ERespTargetOffline, []byte("target module is offline"))
+}
@@ -0,0 +1,32 @@
+/**
+ * @Author: Joey
+ * @Description: 请求与通知的优先级通道，高优先级的消息先于普通消息处理
+ * @Create Date: 2026/10/18 20:00
+ */
+
+package easyCon
+
+// EPriority 消息优先级
+type EPriority int
+
+const (
+	EPriorityNormal EPriority = 0
+	EPriorityHigh   EPriority = 1 // 急停、控制命令等，进入高优先级通道
+)
+
+// routePriority 按设置取路由的优先级
+func (adapter *coreAdapter) routePriority(route string) EPriority {
+	return adapter.setting.RoutePriority[route]
+}
+
+// newReq 构造本模块发出的请求，按路由设置优先级
+func (adapter *coreAdapter) newReq(to, route string, content []byte) PackReq {
+	pack := newReqPack(adapter.setting.Module, to, route, content)
+	pack.Priority = adapter.routePriority(route)
+	return pack
+}
+
+// isHigh 取包头与本模块路由设置中较高的优先级判断
+func (adapter *coreAdapter) isHigh(priority EPriority, route string) bool {
+	return priority >= EPriorityHigh || adapter.routePriority(route) >= EPriorityHigh
+}
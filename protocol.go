@@ -7,6 +7,8 @@
 package easyCon
 
 import (
+	"crypto/rand"
+	"encoding/binary"
 	"encoding/json"
 	"fmt"
 	"sync/atomic"
@@ -14,11 +16,21 @@ import (
 )
 
 var (
-	reqId    uint64
+	reqId    = newIdSeed()
 	logId    uint64
 	noticeId uint64
 )
 
+// newIdSeed 请求 Id 的随机起点，进程重启后 Id 不与之前的重复，响应方的去重缓存不会误判
+// 限制在 2^52 以内，Id 保持在 JSON 数字可精确表示的范围
+func newIdSeed() uint64 {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return uint64(time.Now().UnixNano()) & (1<<52 - 1)
+	}
+	return binary.BigEndian.Uint64(b[:]) & (1<<52 - 1)
+}
+
 func getReqId() uint64 {
 	return atomic.AddUint64(&reqId, 1)
 }
@@ -80,6 +92,8 @@ func newRespPack(req PackReq, code EResp, content []byte) PackResp {
 	originalTo := req.To
 	pack.From = originalTo // 响应的发送者是被请求的模块
 	pack.To = originalFrom // 响应的目标是原始请求者
+	pack.deadline = time.Time{}
+	pack.Priority = EPriorityNormal
 	pack.Content = content
 	return pack
 }
@@ -109,6 +123,11 @@ func BuildReqTopic(prefix, module string) string {
 	return ensureTrailingSlash(prefix) + ReqTopic + module
 }
 
+// BuildBroadcastReqTopic 广播请求主题
+func BuildBroadcastReqTopic(prefix string) string {
+	return BuildReqTopic(prefix, BroadcastModule)
+}
+
 func BuildRespTopic(prefix, module string) string {
 	return ensureTrailingSlash(prefix) + RespTopic + module
 }
@@ -141,19 +160,24 @@ func unmarshalPack(data []byte) (IPack, error) {
 	contentBytes := data[3+headLen:]
 
 	switch packType {
-	case PackTypeReq:
+	case PackTypeReq, PackTypeCancel:
 		var header PackReqHeader
 		if err := json.Unmarshal(headerBytes, &header); err != nil {
 			return nil, fmt.Errorf("failed to unmarshal REQ header: %w", err)
 		}
-		return &PackReq{
+		pack := &PackReq{
 			packBase: packBase{PType: header.PType, Id: header.Id},
 			From:     header.From,
 			To:       header.To,
 			Route:    header.Route,
 			ReqTime:  header.ReqTime,
+			Priority: header.Priority,
 			Content:  contentBytes,
-		}, nil
+		}
+		if header.Budget != 0 {
+			pack.deadline = time.Now().Add(time.Duration(header.Budget) * time.Millisecond)
+		}
+		return pack, nil
 
 	case PackTypeResp:
 		var header PackRespHeader
@@ -183,6 +207,7 @@ func unmarshalPack(data []byte) (IPack, error) {
 			From:     header.From,
 			Route:    header.Route,
 			Retain:   header.Retain,
+			Priority: header.Priority,
 			Content:  contentBytes,
 		}, nil
 
@@ -197,6 +222,9 @@ func unmarshalPack(data []byte) (IPack, error) {
 			Level:    ELogLevel(header.Level),
 			LogTime:  header.LogTime,
 			Content:  string(contentBytes),
+			Error:    header.Error,
+			Caller:   header.Caller,
+			Fields:   header.Fields,
 		}, nil
 
 	default:
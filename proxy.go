@@ -9,7 +9,6 @@ package easyCon
 import (
 	"fmt"
 	"strings"
-	"time"
 )
 
 type proxy struct {
@@ -24,6 +23,7 @@ type proxy struct {
 	proxyNotice       bool
 	proxyRetainNotice bool
 	proxyLog          bool
+	tracer            tracer
 
 	// A 端的请求回调，用于处理反向请求
 	onReqRecA func(PackReq) (EResp, []byte)
@@ -40,6 +40,7 @@ func NewCgoMqttProxy(setting MqttProxySetting, onWrite func([]byte) error, aCall
 		logForwardMode:    setting.LogForwardMode, // 默认为 NONE
 		// 存储A端的OnReqRec回调，用于处理反向请求
 		onReqRecA: aCallbacks.OnReqRec,
+		tracer:    newTracer(setting.Logger, setting.TraceScopes),
 	}
 	sa := CoreSetting{
 		Module:            setting.Module,
@@ -51,6 +52,8 @@ func NewCgoMqttProxy(setting MqttProxySetting, onWrite func([]byte) error, aCall
 		ConnectRetryDelay: 0,
 		IsWaitLink:        false,
 		IsSync:            false,
+		Logger:            setting.Logger,
+		TraceScopes:       setting.TraceScopes,
 	}
 	a, f := NewCGoMonitorWithBroker(sa, AdapterCallBack{
 		OnReqRec:          p.onReqA,
@@ -66,6 +69,8 @@ func NewCgoMqttProxy(setting MqttProxySetting, onWrite func([]byte) error, aCall
 	sb.TimeOut = setting.TimeOut
 	sb.PWD = setting.PWD
 	sb.UID = setting.UID
+	sb.Logger = setting.Logger
+	sb.TraceScopes = setting.TraceScopes
 
 	b := NewMqttMonitor(sb, AdapterCallBack{
 		OnReqRec:          p.onReqB,
@@ -105,6 +110,7 @@ func (p *proxy) onNoticeA(notice PackNotice) {
 		return
 	}
 
+	p.tracer.debug(ETraceProxy, "forward notice", "dir", "A->B", "id", notice.Id, "route", notice.Route)
 	err = p.b.PublishRaw(topic, false, rawData)
 	if err != nil {
 		p.b.Err(fmt.Sprintf("mqttProxy notice (%d) A->B failed", notice.Id), err)
@@ -125,6 +131,7 @@ func (p *proxy) onRetainNoticeA(notice PackNotice) {
 		return
 	}
 
+	p.tracer.debug(ETraceProxy, "forward retain notice", "dir", "A->B", "id", notice.Id, "route", notice.Route)
 	err = p.b.PublishRaw(topic, true, rawData)
 	if err != nil {
 		p.b.Err(fmt.Sprintf("mqttProxy retain notice (%d) A->B failed", notice.Id), err)
@@ -158,6 +165,7 @@ func (p *proxy) onLogA(log PackLog) {
 		return
 	}
 
+	p.tracer.debug(ETraceProxy, "forward log", "dir", "A->B", "id", log.Id, "from", log.From)
 	err = p.b.PublishRaw(topic, false, rawData)
 	if err != nil {
 		p.b.Err(fmt.Sprintf("mqttProxy log (%d) A->B failed", log.Id), err)
@@ -177,6 +185,7 @@ func (p *proxy) onNoticeB(notice PackNotice) {
 		return
 	}
 
+	p.tracer.debug(ETraceProxy, "forward notice", "dir", "B->A", "id", notice.Id, "route", notice.Route)
 	err = p.a.PublishRaw(topic, false, rawData)
 	if err != nil {
 		p.b.Err(fmt.Sprintf("mqttProxy notice (%d) B->A failed", notice.Id), err)
@@ -196,6 +205,7 @@ func (p *proxy) onRetainNoticeB(notice PackNotice) {
 		return
 	}
 
+	p.tracer.debug(ETraceProxy, "forward retain notice", "dir", "B->A", "id", notice.Id, "route", notice.Route)
 	err = p.a.PublishRaw(topic, true, rawData)
 	if err != nil {
 		p.b.Err(fmt.Sprintf("mqttProxy retain notice (%d) B->A failed", notice.Id), err)
@@ -234,6 +244,7 @@ func (p *proxy) onReqA(pack PackReq) (EResp, []byte) {
 		return ERespBypass, []byte{}
 	}
 
+	p.tracer.debug(ETraceProxy, "forward request", "dir", "A->B", "id", pack.Id, "route", pack.Route)
 	err = p.b.PublishRaw(topic, false, modifiedData)
 	if err != nil {
 		p.b.Err(fmt.Sprintf("mqttProxy req (%d) A->B failed", pack.Id), err)
@@ -245,15 +256,17 @@ func (p *proxy) onReqA(pack PackReq) (EResp, []byte) {
 
 // onReqB 收到来自B的请求，转发给A
 func (p *proxy) onReqB(pack PackReq) (EResp, []byte) {
-	now := time.Now().Format("15:04:05.000")
 
 	if strings.HasPrefix(pack.From, p.sb.Module) { //来自自己
 		return ERespBypass, []byte{}
 	}
+	if pack.PType == EPTypeCancel { // 取消包无需执行处理函数
+		return ERespBypass, []byte{}
+	}
 
 	// 直接调用 A 端的回调来处理请求
 	if p.onReqRecA == nil {
-		fmt.Printf("[%s][Proxy-onReqB] ERROR: onReqRecA is nil, cannot process request\n", now)
+		p.tracer.error("proxy cannot forward request, onReqRecA is nil", "id", pack.Id, "route", pack.Route)
 		return ERespBypass, []byte{}
 	}
 
@@ -278,6 +291,7 @@ func (p *proxy) onReqB(pack PackReq) (EResp, []byte) {
 		return ERespBypass, []byte{}
 	}
 
+	p.tracer.debug(ETraceProxy, "forward response", "dir", "B->A", "id", pack.Id, "code", respCode)
 	err = p.b.PublishRaw(respTopic, false, rawData)
 	if err != nil {
 		p.b.Err(fmt.Sprintf("mqttProxy resp (%d) B->A failed", pack.Id), err)
@@ -318,6 +332,7 @@ func (p *proxy) onRespA(resp PackResp) {
 		return
 	}
 
+	p.tracer.debug(ETraceProxy, "forward response", "dir", "A->B", "id", resp.Id, "to", resp.To)
 	err = p.b.PublishRaw(topic, false, rawData)
 	if err != nil {
 		p.b.Err(fmt.Sprintf("mqttProxy resp (%d) A->B failed", resp.Id), err)
@@ -353,6 +368,7 @@ func (p *proxy) onRespB(resp PackResp) {
 		return
 	}
 
+	p.tracer.debug(ETraceProxy, "forward response", "dir", "B->A", "id", resp.Id, "to", targetTo)
 	err = p.a.PublishRaw(topic, false, rawData)
 	if err != nil {
 		p.b.Err(fmt.Sprintf("mqttProxy resp (%d) B->A failed", resp.Id), err)
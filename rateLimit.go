@@ -0,0 +1,145 @@
+/**
+ * @Author: Joey
+ * @Description: 按调用模块与路由的令牌桶限流
+ * @Create Date: 2026/10/18 19:00
+ */
+
+package easyCon
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// AnyKey 限流配置中匹配所有调用模块或路由的键
+const AnyKey = "*"
+
+// RateLimit 令牌桶限流参数
+type RateLimit struct {
+	Rate  float64 // 每秒补充的令牌数，不大于0表示不限制
+	Burst int     // 桶容量，即允许的突发请求数，不大于0时取 Rate 向上取整
+}
+
+// RateLimitSetting 限流配置，键为调用模块/路由，AnyKey 为未单独配置时的默认值
+// 每个调用模块各自一个桶，每个路由由所有调用方共享一个桶
+type RateLimitSetting struct {
+	Callers map[string]RateLimit
+	Routes  map[string]RateLimit
+}
+
+// tokenBucket 令牌桶
+type tokenBucket struct {
+	limit  RateLimit
+	tokens float64
+	last   time.Time
+}
+
+func (limit RateLimit) burst() float64 {
+	if limit.Burst > 0 {
+		return float64(limit.Burst)
+	}
+	if limit.Rate < 1 {
+		return 1
+	}
+	return float64(int(limit.Rate + 0.999999))
+}
+
+func newTokenBucket(limit RateLimit, now time.Time) *tokenBucket {
+	return &tokenBucket{limit: limit, tokens: limit.burst(), last: now}
+}
+
+// refill 按流逝时间补充令牌
+func (bucket *tokenBucket) refill(now time.Time) {
+	bucket.tokens += now.Sub(bucket.last).Seconds() * bucket.limit.Rate
+	if max := bucket.limit.burst(); bucket.tokens > max {
+		bucket.tokens = max
+	}
+	bucket.last = now
+}
+
+// rateLimiter 请求限流器
+type rateLimiter struct {
+	mu      sync.Mutex
+	setting RateLimitSetting
+	callers map[string]*tokenBucket
+	routes  map[string]*tokenBucket
+}
+
+func newRateLimiter(setting RateLimitSetting) *rateLimiter {
+	limiter := &rateLimiter{}
+	limiter.set(setting)
+	return limiter
+}
+
+// set 替换限流配置，已有的桶全部重建
+func (limiter *rateLimiter) set(setting RateLimitSetting) {
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+	limiter.setting = setting
+	limiter.callers = make(map[string]*tokenBucket)
+	limiter.routes = make(map[string]*tokenBucket)
+}
+
+// get 当前限流配置
+func (limiter *rateLimiter) get() RateLimitSetting {
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+	return limiter.setting
+}
+
+// allow 调用模块与路由都取得令牌时放行
+func (limiter *rateLimiter) allow(from, route string) bool {
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+	if len(limiter.setting.Callers) == 0 && len(limiter.setting.Routes) == 0 {
+		return true
+	}
+	now := time.Now()
+	var buckets []*tokenBucket
+	if bucket := bucketOf(limiter.callers, limiter.setting.Callers, from, now); bucket != nil {
+		buckets = append(buckets, bucket)
+	}
+	if bucket := bucketOf(limiter.routes, limiter.setting.Routes, route, now); bucket != nil {
+		buckets = append(buckets, bucket)
+	}
+	// 先确认所有桶都有令牌再扣除，避免一方拒绝时白白消耗另一方
+	for _, bucket := range buckets {
+		bucket.refill(now)
+		if bucket.tokens < 1 {
+			return false
+		}
+	}
+	for _, bucket := range buckets {
+		bucket.tokens--
+	}
+	return true
+}
+
+// bucketOf 取键对应的桶，未配置或不限制时返回 nil
+func bucketOf(buckets map[string]*tokenBucket, limits map[string]RateLimit, key string, now time.Time) *tokenBucket {
+	if bucket, b := buckets[key]; b {
+		return bucket
+	}
+	limit, b := limits[key]
+	if !b {
+		limit, b = limits[AnyKey]
+	}
+	if !b || limit.Rate <= 0 {
+		return nil
+	}
+	bucket := newTokenBucket(limit, now)
+	buckets[key] = bucket
+	return bucket
+}
+
+func (adapter *coreAdapter) onSetRateLimit(pack PackReq) (EResp, []byte) {
+	if len(pack.Content) > 0 {
+		var setting RateLimitSetting
+		if err := json.Unmarshal(pack.Content, &setting); err != nil {
+			return ERespBadReq, []byte(err.Error())
+		}
+		adapter.rateLimiter.set(setting)
+	}
+	return jsonResp(adapter.rateLimiter.get())
+}
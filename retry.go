@@ -0,0 +1,167 @@
+/**
+ * @Author: Joey
+ * @Description: 请求重试策略
+ * @Create Date: 2026/10/17 17:10
+ */
+
+package easyCon
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// RetryPolicy 重试策略
+type RetryPolicy struct {
+	// MaxAttempts 最大尝试次数（含首次请求），小于1时按1处理，即不重试
+	MaxAttempts int
+	// BaseDelay 首次重试前的等待时间，0为立即重试
+	BaseDelay time.Duration
+	// MaxDelay 退避等待时间上限，0为不限制
+	MaxDelay time.Duration
+	// Multiplier 指数退避倍数，小于等于1时为固定间隔
+	Multiplier float64
+	// Jitter 抖动比例(0~1)，实际等待时间在 [delay*(1-Jitter), delay] 之间随机
+	Jitter float64
+	// RetryOn 可重试的响应码，为空时只重试 ERespTimeout
+	RetryOn []EResp
+	// Routes 按路由覆盖的策略，覆盖策略中的 Routes 不生效
+	Routes map[string]RetryPolicy
+}
+
+// NewRetryPolicy 快速新建策略 固定次数立即重试超时的请求
+func NewRetryPolicy(maxAttempts int) *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: maxAttempts,
+	}
+}
+
+// NoRetry 不重试的策略，可用于 Routes 中禁止指定路由重试
+var NoRetry = RetryPolicy{MaxAttempts: 1}
+
+// forRoute 获取路由对应的策略
+func (policy *RetryPolicy) forRoute(route string) RetryPolicy {
+	if p, b := policy.Routes[route]; b {
+		return p
+	}
+	return *policy
+}
+
+// attempts 最大尝试次数
+func (policy RetryPolicy) attempts() int {
+	if policy.MaxAttempts < 1 {
+		return 1
+	}
+	return policy.MaxAttempts
+}
+
+// isRetryable 响应码是否可重试
+func (policy RetryPolicy) isRetryable(code EResp) bool {
+	if len(policy.RetryOn) == 0 {
+		return code == ERespTimeout
+	}
+	for _, c := range policy.RetryOn {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// backoff 第 retry 次重试(从1开始)前的等待时间
+func (policy RetryPolicy) backoff(retry int) time.Duration {
+	if policy.BaseDelay <= 0 {
+		return 0
+	}
+	delay := float64(policy.BaseDelay)
+	if policy.Multiplier > 1 {
+		for i := 1; i < retry; i++ {
+			delay *= policy.Multiplier
+			if policy.MaxDelay > 0 && delay >= float64(policy.MaxDelay) {
+				break
+			}
+		}
+	}
+	if policy.MaxDelay > 0 && delay > float64(policy.MaxDelay) {
+		delay = float64(policy.MaxDelay)
+	}
+	if policy.Jitter > 0 {
+		jitter := policy.Jitter
+		if jitter > 1 {
+			jitter = 1
+		}
+		delay -= delay * jitter * rand.Float64()
+	}
+	return time.Duration(delay)
+}
+
+// budget 按策略完成所有尝试的最长耗时
+func (policy RetryPolicy) budget(timeout time.Duration) time.Duration {
+	noJitter := policy
+	noJitter.Jitter = 0
+	total := timeout * time.Duration(policy.attempts())
+	for retry := 1; retry < policy.attempts(); retry++ {
+		total += noJitter.backoff(retry)
+	}
+	return total
+}
+
+// retryPolicy 获取适配器对应路由的重试策略
+// 未设置 Retry 时沿用 ReTry：ReTry 次尝试，立即重试，只重试超时
+func (adapter *coreAdapter) retryPolicy(route string) RetryPolicy {
+	if adapter.setting.Retry != nil {
+		return adapter.setting.Retry.forRoute(route)
+	}
+	return RetryPolicy{MaxAttempts: adapter.setting.ReTry}
+}
+
+// doReq 按重试策略发送请求
+func (adapter *coreAdapter) doReq(ctx context.Context, pack PackReq, timeout int) (PackResp, error) {
+	if adapter.isKnownOffline(pack.To) {
+		return adapter.newOfflineResp(pack), nil
+	}
+	policy := adapter.retryPolicy(pack.Route)
+	attempts := policy.attempts()
+	// 请求头中携带截止时间，响应方据此丢弃已过期的请求
+	if deadline, ok := ctx.Deadline(); ok {
+		pack.SetDeadline(deadline)
+	} else {
+		pack.SetDeadline(time.Now().Add(policy.budget(adapter.timeoutOf(timeout))))
+	}
+	var resp PackResp
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if attempt > 1 {
+			if delay := policy.backoff(attempt - 1); delay > 0 {
+				timer := time.NewTimer(delay)
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					adapter.giveUp(pack)
+					return newTimeoutResp(pack), ctx.Err()
+				case <-timer.C:
+				}
+			}
+		}
+		resp = adapter.reqInner(ctx, pack, timeout)
+		if err := ctx.Err(); err != nil && resp.RespCode == ERespTimeout {
+			adapter.giveUp(pack)
+			return newTimeoutResp(pack), err
+		}
+		if !policy.isRetryable(resp.RespCode) {
+			return resp, nil
+		}
+	}
+	if resp.RespCode == ERespTimeout {
+		adapter.giveUp(pack)
+		return newTimeoutResp(pack), nil
+	}
+	return resp, nil
+}
+
+// giveUp 放弃等待请求，按设置通知响应方取消
+func (adapter *coreAdapter) giveUp(pack PackReq) {
+	if adapter.setting.IsSendCancel {
+		adapter.sendCancel(pack)
+	}
+}
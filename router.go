@@ -0,0 +1,129 @@
+/**
+ * @Author: Joey
+ * @Description: 请求路由器，按路由注册处理函数，替代 OnReqRec 中的 switch
+ * @Create Date: 2026/10/17 13:40
+ */
+
+package easyCon
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// IReqHandler 请求处理器接口
+type IReqHandler interface {
+	ServeReq(pack PackReq) (EResp, []byte)
+}
+
+// ServeReq 使 ReqHandler 实现 IReqHandler
+func (f ReqHandler) ServeReq(pack PackReq) (EResp, []byte) {
+	return f(pack)
+}
+
+// routePattern 通配路由 如 user/* 以 prefix 为前缀匹配
+type routePattern struct {
+	pattern string
+	prefix  string
+	handler IReqHandler
+}
+
+// Router 路由器
+// 支持精确路由（如 user/get）与通配路由（如 user/*、*），精确路由优先，通配路由按前缀最长优先
+type Router struct {
+	mu       sync.RWMutex
+	routes   map[string]IReqHandler
+	patterns []routePattern
+}
+
+// NewRouter 创建路由器
+func NewRouter() *Router {
+	return &Router{
+		routes: make(map[string]IReqHandler),
+	}
+}
+
+// Handle 注册路由处理器，重复注册时后者覆盖前者
+func (router *Router) Handle(route string, handler IReqHandler) {
+	router.mu.Lock()
+	defer router.mu.Unlock()
+	if !strings.HasSuffix(route, "*") {
+		router.routes[route] = handler
+		return
+	}
+	prefix := strings.TrimSuffix(route, "*")
+	for i, p := range router.patterns {
+		if p.pattern == route {
+			router.patterns[i].handler = handler
+			return
+		}
+	}
+	router.patterns = append(router.patterns, routePattern{
+		pattern: route,
+		prefix:  prefix,
+		handler: handler,
+	})
+	sort.SliceStable(router.patterns, func(i, j int) bool {
+		return len(router.patterns[i].prefix) > len(router.patterns[j].prefix)
+	})
+}
+
+// HandleFunc 注册路由处理函数
+func (router *Router) HandleFunc(route string, f ReqHandler) {
+	router.Handle(route, f)
+}
+
+// Match 查找路由对应的处理器
+func (router *Router) Match(route string) (IReqHandler, bool) {
+	router.mu.RLock()
+	defer router.mu.RUnlock()
+	if h, b := router.routes[route]; b {
+		return h, true
+	}
+	for _, p := range router.patterns {
+		if strings.HasPrefix(route, p.prefix) {
+			return p.handler, true
+		}
+	}
+	return nil, false
+}
+
+// isExact 是否注册了精确路由
+func (router *Router) isExact(route string) bool {
+	router.mu.RLock()
+	defer router.mu.RUnlock()
+	_, b := router.routes[route]
+	return b
+}
+
+// Routes 已注册的路由列表
+func (router *Router) Routes() []string {
+	router.mu.RLock()
+	defer router.mu.RUnlock()
+	routes := make([]string, 0, len(router.routes)+len(router.patterns))
+	for route := range router.routes {
+		routes = append(routes, route)
+	}
+	sort.Strings(routes)
+	for _, p := range router.patterns {
+		routes = append(routes, p.pattern)
+	}
+	return routes
+}
+
+// Len 已注册的路由数量
+func (router *Router) Len() int {
+	router.mu.RLock()
+	defer router.mu.RUnlock()
+	return len(router.routes) + len(router.patterns)
+}
+
+// ServeReq 分发请求，未匹配时返回 ERespRouteNotFind
+func (router *Router) ServeReq(pack PackReq) (EResp, []byte) {
+	h, b := router.Match(pack.Route)
+	if !b {
+		return ERespRouteNotFind, []byte("Route Not Matched")
+	}
+	return h.ServeReq(pack)
+}
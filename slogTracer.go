@@ -0,0 +1,20 @@
+/**
+ * @Author: Joey
+ * @Description: log/slog 的内部日志适配
+ * @Create Date: 2026/10/18 23:00
+ */
+
+package easyCon
+
+import "log/slog"
+
+// NewSlogLogger 以 slog 输出内部日志，logger 为空时使用 slog.Default，每条日志带上模块名
+func NewSlogLogger(logger *slog.Logger, module string) ITraceLogger {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if module != "" {
+		logger = logger.With("module", module)
+	}
+	return logger
+}
@@ -0,0 +1,88 @@
+/**
+ * @Author: Joey
+ * @Description: 适配器运行统计
+ * @Create Date: 2026/10/18 13:30
+ */
+
+package easyCon
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Stats 运行统计快照
+type Stats struct {
+	Module      string
+	StartTime   string
+	ReqSent     uint64 // 发出的请求数（含重试）
+	ReqTimeout  uint64 // 等待超时的请求数
+	RespRec     uint64 // 收到的响应数
+	ReqRec      uint64 // 收到的请求数
+	ReqExpired  uint64 // 到达时已过期被丢弃的请求数
+	ReqReplayed uint64 // 去重缓存回放的请求数
+	ReqCanceled uint64 // 收到的取消数
+	ReqFastFail uint64 // 目标离线直接失败的请求数
+	ReqLimited  uint64 // 被限流拒绝的请求数
+	NoticeSent  uint64
+	NoticeRec   uint64
+	LogRec      uint64
+	// 接收通道满被丢弃的消息数
+	ReqDropped    uint64
+	RespDropped   uint64
+	NoticeDropped uint64
+	LogDropped    uint64
+}
+
+// adapterStats 运行计数器
+type adapterStats struct {
+	startTime     time.Time
+	reqSent       uint64
+	reqTimeout    uint64
+	respRec       uint64
+	reqRec        uint64
+	reqExpired    uint64
+	reqReplayed   uint64
+	reqCanceled   uint64
+	reqFastFail   uint64
+	reqLimited    uint64
+	noticeSent    uint64
+	noticeRec     uint64
+	logRec        uint64
+	reqDropped    uint64
+	respDropped   uint64
+	noticeDropped uint64
+	logDropped    uint64
+}
+
+func newAdapterStats() *adapterStats {
+	return &adapterStats{startTime: time.Now()}
+}
+
+func (s *adapterStats) snapshot(module string) Stats {
+	return Stats{
+		Module:        module,
+		StartTime:     s.startTime.Format("2006-01-02 15:04:05.000"),
+		ReqSent:       atomic.LoadUint64(&s.reqSent),
+		ReqTimeout:    atomic.LoadUint64(&s.reqTimeout),
+		RespRec:       atomic.LoadUint64(&s.respRec),
+		ReqRec:        atomic.LoadUint64(&s.reqRec),
+		ReqExpired:    atomic.LoadUint64(&s.reqExpired),
+		ReqReplayed:   atomic.LoadUint64(&s.reqReplayed),
+		ReqCanceled:   atomic.LoadUint64(&s.reqCanceled),
+		ReqFastFail:   atomic.LoadUint64(&s.reqFastFail),
+		ReqLimited:    atomic.LoadUint64(&s.reqLimited),
+		NoticeSent:    atomic.LoadUint64(&s.noticeSent),
+		NoticeRec:     atomic.LoadUint64(&s.noticeRec),
+		LogRec:        atomic.LoadUint64(&s.logRec),
+		ReqDropped:    atomic.LoadUint64(&s.reqDropped),
+		RespDropped:   atomic.LoadUint64(&s.respDropped),
+		NoticeDropped: atomic.LoadUint64(&s.noticeDropped),
+		LogDropped:    atomic.LoadUint64(&s.logDropped),
+	}
+}
+
+// Stats 获取运行统计
+func (adapter *coreAdapter) Stats() Stats {
+	return adapter.stats.snapshot(adapter.setting.Module)
+}
@@ -0,0 +1,147 @@
+/**
+ * @Author: Joey
+ * @Description: 系统路由，每个适配器都会响应，提供统一的管理接口
+ * @Create Date: 2026/10/18 13:00
+ */
+
+package easyCon
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// 系统路由
+const (
+	RouteGetVersion  = "GetVersion"
+	RouteExit        = "Exit"
+	RoutePing        = "Ping"
+	RouteGetStats    = "GetStats"
+	RouteGetRoutes   = "GetRoutes"
+	RouteGetConfig   = "GetConfig"
+	RouteSetLogLevel = "SetLogLevel"
+	// RouteSetRateLimit 替换限流配置（RateLimitSetting JSON），内容为空时只返回当前配置
+	RouteSetRateLimit = "SetRateLimit"
+)
+
+// protectedSysRoutes 受保护的系统路由，只允许 SysRouteAllow 中的模块调用，默认全部拒绝
+var protectedSysRoutes = map[string]bool{
+	RouteExit:         true,
+	RouteGetConfig:    true,
+	RouteSetLogLevel:  true,
+	RouteSetRateLimit: true,
+}
+
+// RoutesInfo GetRoutes 的响应
+type RoutesInfo struct {
+	System []string
+	Routes []string
+}
+
+// sysRouteHandler 系统路由处理函数
+type sysRouteHandler func(adapter *coreAdapter, pack PackReq) (EResp, []byte)
+
+// sysRouteNames 系统路由列表
+var sysRouteNames = []string{RouteGetVersion, RouteExit, RoutePing, RouteGetStats, RouteGetRoutes, RouteGetConfig, RouteSetLogLevel, RouteSetRateLimit}
+
+var sysRoutes map[string]sysRouteHandler
+
+func init() {
+	sysRoutes = map[string]sysRouteHandler{
+		RouteGetVersion:   (*coreAdapter).onGetVersion,
+		RouteExit:         (*coreAdapter).onExit,
+		RoutePing:         (*coreAdapter).onPing,
+		RouteGetStats:     (*coreAdapter).onGetStats,
+		RouteGetRoutes:    (*coreAdapter).onGetRoutes,
+		RouteGetConfig:    (*coreAdapter).onGetConfig,
+		RouteSetLogLevel:  (*coreAdapter).onSetLogLevel,
+		RouteSetRateLimit: (*coreAdapter).onSetRateLimit,
+	}
+}
+
+// isSysRoute 是否由系统路由处理，禁用系统路由或注册了同名的精确路由时由用户处理
+func (adapter *coreAdapter) isSysRoute(route string) bool {
+	_, b := sysRoutes[route]
+	return b && !adapter.setting.IsDisableSysRoute && !adapter.router.isExact(route)
+}
+
+// serveSysReq 处理系统路由，非系统路由、已禁用或被用户路由覆盖时返回 false
+func (adapter *coreAdapter) serveSysReq(pack PackReq) (EResp, []byte, bool) {
+	if !adapter.isSysRoute(pack.Route) {
+		return 0, nil, false
+	}
+	if protectedSysRoutes[pack.Route] && !adapter.isSysRouteAllowed(pack.From) {
+		return ERespForbidden, []byte("Forbidden"), true
+	}
+	code, content := sysRoutes[pack.Route](adapter, pack)
+	return code, content, true
+}
+
+// isSysRouteAllowed 模块是否可以调用受保护的系统路由，SysRouteAllow 为空时全部拒绝
+func (adapter *coreAdapter) isSysRouteAllowed(module string) bool {
+	for _, m := range adapter.setting.SysRouteAllow {
+		if isModuleMatch(m, module) {
+			return true
+		}
+	}
+	return false
+}
+
+func jsonResp(v any) (EResp, []byte) {
+	bytes, err := json.Marshal(v)
+	if err != nil {
+		return ERespError, ([]byte)(err.Error())
+	}
+	return ERespSuccess, bytes
+}
+
+func (adapter *coreAdapter) onGetVersion(_ PackReq) (EResp, []byte) {
+	var versions []string
+	versions = append(versions, "easy-con:"+getVersion())
+	if adapter.adapterCallback.OnGetVersion != nil {
+		versions = append(versions, adapter.adapterCallback.OnGetVersion()...)
+	}
+	return jsonResp(versions)
+}
+
+func (adapter *coreAdapter) onExit(_ PackReq) (EResp, []byte) {
+	if adapter.adapterCallback.OnExiting != nil {
+		adapter.adapterCallback.OnExiting()
+	}
+	// 先回复再停止
+	time.AfterFunc(time.Millisecond*100, func() {
+		adapter.Stop()
+	})
+	return ERespSuccess, nil
+}
+
+func (adapter *coreAdapter) onPing(pack PackReq) (EResp, []byte) {
+	return ERespSuccess, pack.Content
+}
+
+func (adapter *coreAdapter) onGetStats(_ PackReq) (EResp, []byte) {
+	return jsonResp(adapter.Stats())
+}
+
+func (adapter *coreAdapter) onGetRoutes(_ PackReq) (EResp, []byte) {
+	return jsonResp(RoutesInfo{
+		System: sysRouteNames,
+		Routes: adapter.router.Routes(),
+	})
+}
+
+func (adapter *coreAdapter) onGetConfig(_ PackReq) (EResp, []byte) {
+	setting := adapter.setting
+	setting.LogLevel = adapter.getLogLevel()
+	setting.RateLimit = adapter.rateLimiter.get()
+	return jsonResp(setting)
+}
+
+func (adapter *coreAdapter) onSetLogLevel(pack PackReq) (EResp, []byte) {
+	level := ELogLevel(pack.Content)
+	if _, b := logLevelRank[level]; !b {
+		return ERespBadReq, []byte("unknown log level " + string(level))
+	}
+	adapter.logLevel.Store(level)
+	return ERespSuccess, nil
+}
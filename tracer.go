@@ -0,0 +1,75 @@
+/**
+ * @Author: Joey
+ * @Description: 内部运行日志，可替换的日志接口与按子系统开关的调试跟踪
+ * @Create Date: 2026/10/18 23:00
+ */
+
+package easyCon
+
+// ITraceLogger 内部运行日志接口，args 为交替的键值对，*slog.Logger 可直接使用
+type ITraceLogger interface {
+	Debug(msg string, args ...any)
+	Info(msg string, args ...any)
+	Warn(msg string, args ...any)
+	Error(msg string, args ...any)
+}
+
+// ETraceScope 调试跟踪的子系统
+type ETraceScope string
+
+const (
+	ETraceCore   ETraceScope = "CORE"   // 请求发送、响应匹配
+	ETraceCgo    ETraceScope = "CGO"    // CGO适配器收包与订阅
+	ETraceBroker ETraceScope = "BROKER" // CgoBroker 转发与订阅
+	ETraceProxy  ETraceScope = "PROXY"  // 代理转发
+	ETraceAll    ETraceScope = "ALL"    // 全部子系统
+)
+
+// nopLogger 默认的空日志
+type nopLogger struct{}
+
+func (nopLogger) Debug(string, ...any) {}
+func (nopLogger) Info(string, ...any)  {}
+func (nopLogger) Warn(string, ...any)  {}
+func (nopLogger) Error(string, ...any) {}
+
+// tracer 内部日志，调试信息只在子系统开启跟踪时输出，警告与错误总是输出
+type tracer struct {
+	logger ITraceLogger
+	scopes map[ETraceScope]bool
+}
+
+func newTracer(logger ITraceLogger, scopes []ETraceScope) tracer {
+	if logger == nil {
+		logger = nopLogger{}
+	}
+	t := tracer{logger: logger, scopes: make(map[ETraceScope]bool, len(scopes))}
+	for _, scope := range scopes {
+		t.scopes[scope] = true
+	}
+	return t
+}
+
+// on 子系统是否开启跟踪
+func (t tracer) on(scope ETraceScope) bool {
+	return t.scopes[scope] || t.scopes[ETraceAll]
+}
+
+func (t tracer) debug(scope ETraceScope, msg string, args ...any) {
+	if t.logger == nil || !t.on(scope) {
+		return
+	}
+	t.logger.Debug(msg, append(args, "scope", scope)...)
+}
+
+func (t tracer) warn(msg string, args ...any) {
+	if t.logger != nil {
+		t.logger.Warn(msg, args...)
+	}
+}
+
+func (t tracer) error(msg string, args ...any) {
+	if t.logger != nil {
+		t.logger.Error(msg, args...)
+	}
+}
@@ -0,0 +1,69 @@
+/**
+ * @Author: Joey
+ * @Description: 类型化请求/响应辅助函数
+ * @Create Date: 2026/10/17 15:45
+ */
+
+package easyCon
+
+import "fmt"
+
+// RespError 非成功响应转换成的错误
+type RespError struct {
+	Code    EResp
+	Content []byte
+}
+
+func (e *RespError) Error() string {
+	return fmt.Sprintf("resp code %d: %s", e.Code, string(e.Content))
+}
+
+// Call 类型化请求，使用 JsonCodec 编解码
+// 响应码不为 ERespSuccess 时返回 *RespError
+func Call[Req, Resp any](adapter IAdapter, module, route string, req Req) (Resp, error) {
+	return CallWithCodec[Req, Resp](adapter, JsonCodec, module, route, req)
+}
+
+// CallWithCodec 使用指定编解码器的类型化请求
+func CallWithCodec[Req, Resp any](adapter IAdapter, codec Codec, module, route string, req Req) (Resp, error) {
+	var resp Resp
+	content, err := codec.Marshal(req)
+	if err != nil {
+		return resp, fmt.Errorf("marshal %s req failed: %w", route, err)
+	}
+	pack := adapter.Req(module, route, content)
+	if pack.RespCode != ERespSuccess {
+		return resp, &RespError{Code: pack.RespCode, Content: pack.Content}
+	}
+	if len(pack.Content) == 0 {
+		return resp, nil
+	}
+	if err = codec.Unmarshal(pack.Content, &resp); err != nil {
+		return resp, fmt.Errorf("unmarshal %s resp failed: %w", route, err)
+	}
+	return resp, nil
+}
+
+// HandleTyped 注册类型化路由处理函数，使用 JsonCodec 编解码
+// 请求内容为空时 req 为零值，解码失败时直接返回 ERespBadReq
+func HandleTyped[Req, Resp any](adapter IAdapter, route string, f func(pack PackReq, req Req) (EResp, Resp)) {
+	HandleTypedWithCodec[Req, Resp](adapter, JsonCodec, route, f)
+}
+
+// HandleTypedWithCodec 使用指定编解码器注册类型化路由处理函数
+func HandleTypedWithCodec[Req, Resp any](adapter IAdapter, codec Codec, route string, f func(pack PackReq, req Req) (EResp, Resp)) {
+	adapter.HandleFunc(route, func(pack PackReq) (EResp, []byte) {
+		var req Req
+		if len(pack.Content) > 0 {
+			if err := codec.Unmarshal(pack.Content, &req); err != nil {
+				return ERespBadReq, []byte(err.Error())
+			}
+		}
+		code, resp := f(pack, req)
+		content, err := codec.Marshal(resp)
+		if err != nil {
+			return ERespError, []byte(err.Error())
+		}
+		return code, content
+	})
+}
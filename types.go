@@ -15,6 +15,7 @@ const (
 	PackTypeResp   byte = 0x02
 	PackTypeNotice byte = 0x03
 	PackTypeLog    byte = 0x04
+	PackTypeCancel byte = 0x05
 )
 
 // Topic 常量
@@ -24,6 +25,8 @@ const (
 	LogTopic          string = "Log"
 	ReqTopic          string = "Request/"
 	RespTopic         string = "Response/"
+	// BroadcastModule 广播请求的目标模块，对应主题 Request/*
+	BroadcastModule string = "*"
 )
 
 // PackBaseHeader 包基础头
@@ -39,6 +42,9 @@ type PackReqHeader struct {
 	ReqTime string
 	To      string
 	Route   string
+	// Budget 发送时距请求截止时间的剩余毫秒数，0为不限制，负数为已过期
+	Budget   int64     `json:",omitempty"`
+	Priority EPriority `json:",omitempty"`
 }
 
 // PackRespHeader 响应包头
@@ -51,9 +57,10 @@ type PackRespHeader struct {
 // PackNoticeHeader 通知包头
 type PackNoticeHeader struct {
 	PackBaseHeader
-	From   string
-	Route  string
-	Retain bool
+	From     string
+	Route    string
+	Retain   bool
+	Priority EPriority `json:",omitempty"`
 }
 
 // PackLogHeader 日志包头
@@ -63,6 +70,10 @@ type PackLogHeader struct {
 	Level   string
 	LogTime string
 	Error   string
+	// Caller 调用位置 目录/文件:行号
+	Caller string `json:",omitempty"`
+	// Fields 结构化键值
+	Fields map[string]any `json:",omitempty"`
 }
 
 // EPType 包类型枚举
@@ -73,6 +84,7 @@ const (
 	EPTypeResp   EPType = "RESP"
 	EPTypeNotice EPType = "NOTICE"
 	EPTypeLog    EPType = "LOG"
+	EPTypeCancel EPType = "CANCEL"
 )
 
 // EProxyMode 代理模式
@@ -85,6 +97,7 @@ const (
 	EStatusConnecting EStatus = "Connecting"
 	EStatusLinked     EStatus = "Linked"
 	EStatusLinkLost   EStatus = "LinkLost"
+	EStatusStopping   EStatus = "Stopping"
 	EStatusStopped    EStatus = "Stopped"
 )
 
@@ -92,13 +105,17 @@ const (
 type EResp int
 
 const (
-	ERespUnLinked     EResp = 0
-	ERespSuccess      EResp = 200
-	ERespBadReq       EResp = 400
-	ERespRouteNotFind EResp = 404
-	ERespError        EResp = 500
-	ERespTimeout      EResp = 408
-	ERespBypass       EResp = 100
+	ERespUnLinked           EResp = 0
+	ERespSuccess            EResp = 200
+	ERespBadReq             EResp = 400
+	ERespForbidden          EResp = 403
+	ERespRouteNotFind       EResp = 404
+	ERespError              EResp = 500
+	ERespServiceUnavailable EResp = 503
+	ERespTimeout            EResp = 408
+	ERespTargetOffline      EResp = 410
+	ERespTooManyRequests    EResp = 429
+	ERespBypass             EResp = 100
 )
 
 // ELogMode 日志模式枚举
@@ -109,17 +126,35 @@ const (
 	ELogModeConsole ELogMode = "CONSOLE"
 	ELogModeUpload  ELogMode = "UPLOAD"
 	ELogModeAll     ELogMode = "ALL"
+	// ELogModeFile 只写入本地文件，见 CoreSetting.LogFile
+	ELogModeFile ELogMode = "FILE"
 )
 
 // ELogLevel 日志级别枚举
 type ELogLevel string
 
 const (
+	ELogLevelTrace   ELogLevel = "TRACE"
 	ELogLevelDebug   ELogLevel = "DEBUG"
+	ELogLevelInfo    ELogLevel = "INFO"
 	ELogLevelWarning ELogLevel = "WARNING"
 	ELogLevelError   ELogLevel = "ERROR"
 )
 
+// logLevelRank 日志级别排序，用于按最低级别过滤
+var logLevelRank = map[ELogLevel]int{
+	ELogLevelTrace:   0,
+	ELogLevelDebug:   1,
+	ELogLevelInfo:    2,
+	ELogLevelWarning: 3,
+	ELogLevelError:   4,
+}
+
+// isLevelEnabled level 不低于最低级别 min，min 为空时全部输出
+func isLevelEnabled(level, min ELogLevel) bool {
+	return logLevelRank[level] >= logLevelRank[min]
+}
+
 // ELogForwardMode 日志转发模式枚举
 type ELogForwardMode string
 
@@ -132,6 +167,8 @@ const (
 // GetStatusName 获取状态名称
 func GetStatusName(status EStatus) string {
 	switch status {
+	case EStatusStopping:
+		return "Stopping"
 	case EStatusStopped:
 		return "Stopped"
 	case EStatusLinkLost:
@@ -0,0 +1,108 @@
+/**
+ * @Author: Joey
+ * @Description: 跨模块请求取消单元测试
+ * @Create Date: 2026/10/18 10:00
+ */
+
+package unitTest
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	easyCon "github.com/qiu-tec/easy-con.golang"
+)
+
+func TestCancelPackRoundTrip(t *testing.T) {
+	pack := easyCon.PackReq{From: "A", To: "B", Route: "Export"}
+	pack.PType = easyCon.EPTypeCancel
+	pack.Id = 7
+	data, err := pack.Raw()
+	if err != nil {
+		t.Fatalf("Raw() failed: %v", err)
+	}
+	if data[0] != easyCon.PackTypeCancel {
+		t.Fatalf("PackType mismatch: got 0x%02x", data[0])
+	}
+	decoded, err := easyCon.UnmarshalPack(data)
+	if err != nil {
+		t.Fatalf("UnmarshalPack() failed: %v", err)
+	}
+	req, ok := decoded.(*easyCon.PackReq)
+	if !ok || req.PType != easyCon.EPTypeCancel || req.Id != 7 || req.Route != "Export" {
+		t.Fatalf("decoded %+v", decoded)
+	}
+}
+
+func TestCrossModuleCancel(t *testing.T) {
+	broker := easyCon.NewCgoBroker()
+	setting := newCgoSetting("CancelClient")
+	setting.IsSendCancel = true
+	client := newCgoModule(&broker, setting, easyCon.AdapterCallBack{})
+	server := newCgoModule(&broker, newCgoSetting("CancelServer"), easyCon.AdapterCallBack{})
+	canceled := make(chan struct{})
+	server.HandleCtx("Export", func(ctx context.Context, pack easyCon.PackReq) (easyCon.EResp, []byte) {
+		select {
+		case <-ctx.Done():
+			close(canceled)
+			return easyCon.ERespError, nil
+		case <-time.After(time.Second * 5):
+			return easyCon.ERespSuccess, nil
+		}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	defer cancel()
+	_, err := client.ReqCtx(ctx, "CancelServer", "Export", nil)
+	if err == nil {
+		t.Fatalf("request should time out")
+	}
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Fatalf("handler was not canceled")
+	}
+}
+
+func TestMonitorIgnoresCancel(t *testing.T) {
+	broker := easyCon.NewCgoBroker()
+	var lock sync.Mutex
+	var seen []easyCon.EPType
+	monitor, onRead := easyCon.NewCGoMonitorWithBroker(newCgoSetting("CancelMonitor"), easyCon.AdapterCallBack{
+		OnReqRec: func(pack easyCon.PackReq) (easyCon.EResp, []byte) {
+			if pack.Route == "Export" {
+				lock.Lock()
+				seen = append(seen, pack.PType)
+				lock.Unlock()
+			}
+			return easyCon.ERespBypass, nil
+		},
+	}, broker.Publish, broker.Publish)
+	defer monitor.Stop()
+	broker.RegClient("CancelMonitor", onRead)
+	time.Sleep(time.Millisecond * 50)
+
+	setting := newCgoSetting("MonitorCancelClient")
+	setting.IsSendCancel = true
+	client := newCgoModule(&broker, setting, easyCon.AdapterCallBack{})
+	defer client.Stop()
+	server := newCgoModule(&broker, newCgoSetting("MonitorCancelServer"), easyCon.AdapterCallBack{})
+	defer server.Stop()
+	server.HandleCtx("Export", func(ctx context.Context, pack easyCon.PackReq) (easyCon.EResp, []byte) {
+		<-ctx.Done()
+		return easyCon.ERespError, nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	defer cancel()
+	_, _ = client.ReqCtx(ctx, "MonitorCancelServer", "Export", nil)
+	time.Sleep(time.Millisecond * 100)
+	// 监视器只看到请求，看不到取消包
+	lock.Lock()
+	defer lock.Unlock()
+	if len(seen) != 1 || seen[0] != easyCon.EPTypeReq {
+		t.Errorf("monitor saw %v", seen)
+	}
+}
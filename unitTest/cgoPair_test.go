@@ -0,0 +1,33 @@
+/**
+ * @Author: Joey
+ * @Description: 基于CgoBroker的进程内测试工具，无需外部MQTT Broker
+ * @Create Date: 2026/10/17 10:12
+ */
+
+package unitTest
+
+import (
+	"time"
+
+	easyCon "github.com/qiu-tec/easy-con.golang"
+)
+
+// newCgoSetting 测试用CGO设置
+func newCgoSetting(module string) easyCon.CoreSetting {
+	return easyCon.CoreSetting{
+		Module:            module,
+		TimeOut:           time.Second,
+		ReTry:             3,
+		LogMode:           easyCon.ELogModeNone,
+		ChannelBufferSize: 100,
+	}
+}
+
+// newCgoModule 创建并注册一个挂在broker上的CGO模块
+func newCgoModule(broker *easyCon.CgoBroker, setting easyCon.CoreSetting, cb easyCon.AdapterCallBack) easyCon.IAdapter {
+	adapter, onRead := easyCon.NewCgoAdapter(setting, cb, broker.Publish)
+	broker.RegClient(setting.Module, onRead)
+	// 等待订阅完成
+	time.Sleep(time.Millisecond * 50)
+	return adapter
+}
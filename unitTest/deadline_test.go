@@ -0,0 +1,87 @@
+/**
+ * @Author: Joey
+ * @Description: 请求截止时间传递单元测试
+ * @Create Date: 2026/10/18 11:00
+ */
+
+package unitTest
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	easyCon "github.com/qiu-tec/easy-con.golang"
+)
+
+func TestDeadlinePropagation(t *testing.T) {
+	broker := easyCon.NewCgoBroker()
+	client := newCgoModule(&broker, newCgoSetting("DlClient"), easyCon.AdapterCallBack{})
+	server := newCgoModule(&broker, newCgoSetting("DlServer"), easyCon.AdapterCallBack{})
+	var count int32
+	var remain int64
+	server.HandleCtx("Work", func(ctx context.Context, pack easyCon.PackReq) (easyCon.EResp, []byte) {
+		atomic.AddInt32(&count, 1)
+		if deadline, ok := ctx.Deadline(); ok {
+			atomic.StoreInt64(&remain, int64(time.Until(deadline)))
+		}
+		return easyCon.ERespSuccess, nil
+	})
+
+	// 已过期的请求到达后直接丢弃
+	expired := easyCon.PackReq{From: "DlClient", To: "DlServer", Route: "Work"}
+	expired.PType = easyCon.EPTypeReq
+	expired.Id = 1 << 40
+	expired.SetDeadline(time.Now().Add(-time.Second))
+	if err := client.Publish(easyCon.BuildReqTopic("", "DlServer"), false, &expired); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	time.Sleep(time.Millisecond * 100)
+	if n := atomic.LoadInt32(&count); n != 0 {
+		t.Fatalf("expired request executed %d times", n)
+	}
+
+	// 截止时间随请求头传递给处理函数
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*500)
+	defer cancel()
+	resp, err := client.ReqCtx(ctx, "DlServer", "Work", nil)
+	if err != nil || resp.RespCode != easyCon.ERespSuccess {
+		t.Fatalf("work: %d %v", resp.RespCode, err)
+	}
+	r := time.Duration(atomic.LoadInt64(&remain))
+	if r <= 0 || r > time.Millisecond*500 {
+		t.Errorf("handler deadline remain %v", r)
+	}
+}
+
+// 包头传递剩余时间，收到时按本机时间重建截止时间，不受时钟偏差影响
+func TestDeadlineBudgetRoundTrip(t *testing.T) {
+	pack := easyCon.PackReq{From: "A", To: "B", Route: "Work"}
+	pack.PType = easyCon.EPTypeReq
+	pack.SetDeadline(time.Now().Add(time.Millisecond * 500))
+	data, err := pack.Raw()
+	if err != nil {
+		t.Fatalf("Raw() failed: %v", err)
+	}
+	headLen := int(data[1])<<8 | int(data[2])
+	if header := string(data[3 : 3+headLen]); !strings.Contains(header, `"Budget":`) || strings.Contains(header, "Deadline") {
+		t.Fatalf("header %s", header)
+	}
+	decoded, err := easyCon.UnmarshalPack(data)
+	if err != nil {
+		t.Fatalf("UnmarshalPack() failed: %v", err)
+	}
+	deadline, ok := decoded.(*easyCon.PackReq).DeadlineTime()
+	if remain := time.Until(deadline); !ok || remain <= time.Millisecond*400 || remain > time.Millisecond*500 {
+		t.Errorf("rebuilt deadline remain %v %v", remain, ok)
+	}
+
+	pack.SetDeadline(time.Now().Add(-time.Hour))
+	data, _ = pack.Raw()
+	decoded, _ = easyCon.UnmarshalPack(data)
+	if deadline, ok := decoded.(*easyCon.PackReq).DeadlineTime(); !ok || time.Now().Before(deadline) {
+		t.Errorf("expired request rebuilt as %v %v", deadline, ok)
+	}
+}
@@ -0,0 +1,59 @@
+/**
+ * @Author: Joey
+ * @Description: 停止时排空单元测试
+ * @Create Date: 2026/10/18 21:30
+ */
+
+package unitTest
+
+import (
+	"testing"
+	"time"
+
+	easyCon "github.com/qiu-tec/easy-con.golang"
+)
+
+func TestGracefulStop(t *testing.T) {
+	broker := easyCon.NewCgoBroker()
+	client := newCgoModule(&broker, newCgoSetting("DrainClient"), easyCon.AdapterCallBack{})
+	server := newCgoModule(&broker, newCgoSetting("DrainServer"), easyCon.AdapterCallBack{})
+	server.HandleFunc("Slow", func(easyCon.PackReq) (easyCon.EResp, []byte) {
+		time.Sleep(time.Millisecond * 300)
+		return easyCon.ERespSuccess, []byte("done")
+	})
+
+	inFlight := make(chan easyCon.PackResp, 1)
+	go func() { inFlight <- client.Req("DrainServer", "Slow", nil) }()
+	// 服务端自己等待一个不会回复的模块
+	pending := make(chan easyCon.PackResp, 1)
+	go func() { pending <- server.ReqWithTimeout("DrainNobody", "Any", nil, 5000) }()
+	time.Sleep(time.Millisecond * 50)
+
+	start := time.Now()
+	go server.Stop()
+	time.Sleep(time.Millisecond * 50)
+
+	// 排空期间的新请求立即被拒绝
+	if resp := client.Req("DrainServer", "Slow", nil); resp.RespCode != easyCon.ERespServiceUnavailable {
+		t.Errorf("new request during drain: %d", resp.RespCode)
+	}
+	// 处理中的请求照常完成并回复
+	if resp := <-inFlight; resp.RespCode != easyCon.ERespSuccess || string(resp.Content) != "done" {
+		t.Errorf("in-flight request: %d %s", resp.RespCode, resp.Content)
+	}
+	// 排空后等待响应的调用立即失败，不再等满超时
+	select {
+	case resp := <-pending:
+		if resp.RespCode != easyCon.ERespUnLinked {
+			t.Errorf("pending waiter: %d", resp.RespCode)
+		}
+		if cost := time.Since(start); cost > time.Second {
+			t.Errorf("pending waiter released after %v", cost)
+		}
+	case <-time.After(time.Second * 2):
+		t.Fatalf("pending waiter was not released")
+	}
+	if resp := server.Req("DrainClient", easyCon.RoutePing, nil); resp.RespCode != easyCon.ERespUnLinked {
+		t.Errorf("request after stop: %d", resp.RespCode)
+	}
+}
@@ -0,0 +1,84 @@
+/**
+ * @Author: Joey
+ * @Description: 服务端请求去重单元测试
+ * @Create Date: 2026/10/17 16:50
+ */
+
+package unitTest
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	easyCon "github.com/qiu-tec/easy-con.golang"
+)
+
+func TestIdempotentRetry(t *testing.T) {
+	for _, ttl := range []time.Duration{0, time.Minute} {
+		broker := easyCon.NewCgoBroker()
+		client := newCgoModule(&broker, newCgoSetting("IdemClient"), easyCon.AdapterCallBack{})
+		setting := newCgoSetting("IdemServer")
+		setting.IdempotentTTL = ttl
+		var count int32
+		_ = newCgoModule(&broker, setting, easyCon.AdapterCallBack{
+			OnReqRec: func(pack easyCon.PackReq) (easyCon.EResp, []byte) {
+				atomic.AddInt32(&count, 1)
+				time.Sleep(time.Millisecond * 150)
+				return easyCon.ERespSuccess, []byte("charged")
+			},
+		})
+
+		// 第一次等待100ms超时，重试时原请求仍在处理
+		resp := client.ReqWithTimeout("IdemServer", "Charge", nil, 100)
+		if resp.RespCode != easyCon.ERespSuccess {
+			t.Fatalf("ttl %v: resp code %d", ttl, resp.RespCode)
+		}
+		time.Sleep(time.Millisecond * 300)
+		n := atomic.LoadInt32(&count)
+		if ttl > 0 && n != 1 {
+			t.Errorf("handler executed %d times with dedup", n)
+		}
+		if ttl == 0 && n < 2 {
+			t.Errorf("handler executed %d times without dedup", n)
+		}
+	}
+}
+
+func TestIdempotentAbortedRetry(t *testing.T) {
+	broker := easyCon.NewCgoBroker()
+	client := newCgoModule(&broker, newCgoSetting("IdemAbortClient"), easyCon.AdapterCallBack{})
+	setting := newCgoSetting("IdemAbortServer")
+	setting.IdempotentTTL = time.Minute
+	setting.RouteConcurrency = map[string]int{"Work": 1}
+	server := newCgoModule(&broker, setting, easyCon.AdapterCallBack{})
+	var count int32
+	server.HandleFunc("Work", func(pack easyCon.PackReq) (easyCon.EResp, []byte) {
+		atomic.AddInt32(&count, 1)
+		time.Sleep(time.Millisecond * 200)
+		return easyCon.ERespSuccess, nil
+	})
+
+	// 占住路由名额，请求在等待名额期间过期放弃
+	client.ReqAsync("IdemAbortServer", "Work", nil)
+	time.Sleep(time.Millisecond * 20)
+	pack := easyCon.PackReq{From: "IdemAbortClient", To: "IdemAbortServer", Route: "Work"}
+	pack.PType = easyCon.EPTypeReq
+	pack.Id = 1 << 40
+	pack.SetDeadline(time.Now().Add(time.Millisecond * 50))
+	topic := easyCon.BuildReqTopic("", "IdemAbortServer")
+	if err := client.Publish(topic, false, &pack); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	time.Sleep(time.Millisecond * 100)
+
+	// 同一请求重试，应重新处理而不是被当作处理中丢弃
+	pack.SetDeadline(time.Now().Add(time.Second))
+	if err := client.Publish(topic, false, &pack); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	time.Sleep(time.Millisecond * 500)
+	if n := atomic.LoadInt32(&count); n != 2 {
+		t.Errorf("handler executed %d times, want 2", n)
+	}
+}
@@ -0,0 +1,77 @@
+/**
+ * @Author: Joey
+ * @Description: 拦截器单元测试
+ * @Create Date: 2026/10/17 15:00
+ */
+
+package unitTest
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	easyCon "github.com/qiu-tec/easy-con.golang"
+)
+
+func TestInterceptor(t *testing.T) {
+	broker := easyCon.NewCgoBroker()
+	client := newCgoModule(&broker, newCgoSetting("InterceptClient"), easyCon.AdapterCallBack{})
+	server := newCgoModule(&broker, newCgoSetting("InterceptServer"), easyCon.AdapterCallBack{})
+	server.HandleFunc("echo", func(pack easyCon.PackReq) (easyCon.EResp, []byte) {
+		return easyCon.ERespSuccess, pack.Content
+	})
+
+	var order []string
+	// 服务端鉴权：没有token直接拒绝，有token则去掉token后继续
+	server.Use(easyCon.Interceptor{
+		OnReqRec: func(pack easyCon.PackReq, next easyCon.ReqHandler) (easyCon.EResp, []byte) {
+			order = append(order, "outer")
+			if len(pack.Content) < 4 || string(pack.Content[:4]) != "tok:" {
+				return easyCon.ERespBadReq, []byte("no token")
+			}
+			pack.Content = pack.Content[4:]
+			return next(pack)
+		},
+	}, easyCon.Interceptor{
+		OnReqRec: func(pack easyCon.PackReq, next easyCon.ReqHandler) (easyCon.EResp, []byte) {
+			order = append(order, "inner")
+			return next(pack)
+		},
+	})
+
+	var latency int64
+	client.Use(easyCon.Interceptor{
+		OnReq: func(pack easyCon.PackReq, next easyCon.ReqInvoker) easyCon.PackResp {
+			start := time.Now()
+			pack.Content = append([]byte("tok:"), pack.Content...)
+			resp := next(pack)
+			atomic.StoreInt64(&latency, int64(time.Since(start)))
+			return resp
+		},
+		OnNotice: func(pack easyCon.PackNotice, next easyCon.NoticeSender) error {
+			return errors.New("notice blocked")
+		},
+	})
+
+	resp := client.Req("InterceptServer", "echo", []byte("hi"))
+	if resp.RespCode != easyCon.ERespSuccess || string(resp.Content) != "hi" {
+		t.Fatalf("echo: %d %s", resp.RespCode, resp.Content)
+	}
+	if atomic.LoadInt64(&latency) <= 0 {
+		t.Errorf("latency not observed")
+	}
+	if len(order) != 2 || order[0] != "outer" || order[1] != "inner" {
+		t.Errorf("interceptor order %v", order)
+	}
+	if err := client.SendNotice("x", nil); err == nil {
+		t.Errorf("notice should be short-circuited")
+	}
+
+	// 服务端短路
+	resp = server.Req("InterceptServer", "echo", []byte("hi"))
+	if resp.RespCode != easyCon.ERespBadReq {
+		t.Errorf("want bad req, got %d", resp.RespCode)
+	}
+}
@@ -0,0 +1,136 @@
+/**
+ * @Author: Joey
+ * @Description: 适配器生命周期单元测试
+ * @Create Date: 2026/10/18 22:30
+ */
+
+package unitTest
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	easyCon "github.com/qiu-tec/easy-con.golang"
+)
+
+func TestLifecycle(t *testing.T) {
+	broker := easyCon.NewCgoBroker()
+	server := newCgoModule(&broker, newCgoSetting("LifeServer"), easyCon.AdapterCallBack{})
+	server.HandleFunc("Echo", reply("pong"))
+	var lock sync.Mutex
+	var statuses []easyCon.EStatus
+	client := newCgoModule(&broker, newCgoSetting("LifeClient"), easyCon.AdapterCallBack{
+		OnStatusChanged: func(status easyCon.EStatus) {
+			lock.Lock()
+			statuses = append(statuses, status)
+			lock.Unlock()
+		},
+	})
+	if status := client.Status(); status != easyCon.EStatusLinked {
+		t.Fatalf("status after create %s", status)
+	}
+
+	// 并发重复停止
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			client.Stop()
+		}()
+	}
+	wg.Wait()
+	if status := client.Status(); status != easyCon.EStatusStopped {
+		t.Fatalf("status after stop %s", status)
+	}
+	if resp := client.Req("LifeServer", "Echo", nil); resp.RespCode != easyCon.ERespUnLinked {
+		t.Fatalf("request while stopped: %d", resp.RespCode)
+	}
+
+	// 并发重复启动
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			client.Start()
+		}()
+	}
+	wg.Wait()
+	time.Sleep(time.Millisecond * 50)
+	if resp := client.Req("LifeServer", "Echo", nil); resp.RespCode != easyCon.ERespSuccess {
+		t.Fatalf("request after restart: %d", resp.RespCode)
+	}
+	lock.Lock()
+	want := []easyCon.EStatus{easyCon.EStatusConnecting, easyCon.EStatusLinked, easyCon.EStatusStopping, easyCon.EStatusStopped, easyCon.EStatusConnecting, easyCon.EStatusLinked}
+	if len(statuses) != len(want) {
+		t.Fatalf("status changes %v", statuses)
+	}
+	for i := range want {
+		if statuses[i] != want[i] {
+			t.Fatalf("status changes %v, want %v", statuses, want)
+		}
+	}
+	lock.Unlock()
+}
+
+func TestResetNoLeak(t *testing.T) {
+	broker := easyCon.NewCgoBroker()
+	server := newCgoModule(&broker, newCgoSetting("ResetServer"), easyCon.AdapterCallBack{})
+	server.HandleFunc("Echo", reply("pong"))
+	setting := newCgoSetting("ResetClient")
+	setting.ReqWorkers = 4
+	setting.OrderKey = easyCon.EOrderRoute
+	client := newCgoModule(&broker, setting, easyCon.AdapterCallBack{})
+
+	client.Reset()
+	time.Sleep(time.Millisecond * 50)
+	before := runtime.NumGoroutine()
+	for i := 0; i < 10; i++ {
+		client.Reset()
+	}
+	time.Sleep(time.Millisecond * 100)
+	if after := runtime.NumGoroutine(); after > before+2 {
+		t.Fatalf("goroutines grew from %d to %d across resets", before, after)
+	}
+	if resp := client.Req("ResetServer", "Echo", nil); resp.RespCode != easyCon.ERespSuccess {
+		t.Fatalf("request after resets: %d", resp.RespCode)
+	}
+	server.Stop()
+	client.Stop()
+}
+
+func TestStatusCallbackRestart(t *testing.T) {
+	broker := easyCon.NewCgoBroker()
+	var client easyCon.IAdapter
+	var once sync.Once
+	restarted := make(chan struct{})
+	client = newCgoModule(&broker, newCgoSetting("RestartClient"), easyCon.AdapterCallBack{
+		OnStatusChanged: func(status easyCon.EStatus) {
+			// 回调中调用 Start 不能死锁
+			if status == easyCon.EStatusStopped {
+				once.Do(func() {
+					client.Start()
+					close(restarted)
+				})
+			}
+		},
+	})
+	done := make(chan struct{})
+	go func() {
+		client.Stop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("stop deadlocked in status callback")
+	}
+	<-restarted
+	time.Sleep(time.Millisecond * 50)
+	if status := client.Status(); status != easyCon.EStatusLinked {
+		t.Fatalf("status after restart in callback %s", status)
+	}
+	client.Stop()
+}
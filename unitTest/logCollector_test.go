@@ -0,0 +1,175 @@
+/**
+ * @Author: Joey
+ * @Description: 日志收集器单元测试
+ * @Create Date: 2026/10/18 14:40
+ */
+
+package unitTest
+
+import (
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	easyCon "github.com/qiu-tec/easy-con.golang"
+)
+
+// newCgoCollector 创建并注册挂在broker上的日志收集器
+func newCgoCollector(t *testing.T, broker *easyCon.CgoBroker, collect easyCon.LogCollectorSetting) *easyCon.LogCollector {
+	setting := newCgoSetting("LogCollector")
+	collector, onRead, err := easyCon.NewCgoLogCollector(setting, collect, easyCon.AdapterCallBack{}, broker.Publish, broker.Publish)
+	if err != nil {
+		t.Fatalf("new collector: %v", err)
+	}
+	broker.RegClient(setting.Module, onRead)
+	time.Sleep(time.Millisecond * 50)
+	return collector
+}
+
+func TestLogCollectorQuery(t *testing.T) {
+	broker := easyCon.NewCgoBroker()
+	dir := t.TempDir()
+	collector := newCgoCollector(t, &broker, easyCon.LogCollectorSetting{Dir: dir})
+
+	var workers []easyCon.IAdapter
+	for _, module := range []string{"Worker1", "Worker2"} {
+		setting := newCgoSetting(module)
+		setting.LogMode = easyCon.ELogModeUpload
+		worker := newCgoModule(&broker, setting, easyCon.AdapterCallBack{})
+		defer worker.Stop()
+		workers = append(workers, worker)
+	}
+	workers[0].Debug("worker1 debug")
+	workers[0].Err("worker1 failed", errors.New("disk full"))
+	workers[1].Info("worker2 info")
+	workers[1].Log(easyCon.ELogLevelWarning, "worker2 slow", "job", "backup")
+	time.Sleep(time.Millisecond * 200)
+
+	query := func(q easyCon.LogQuery) easyCon.LogQueryResult {
+		t.Helper()
+		result, err := easyCon.Call[easyCon.LogQuery, easyCon.LogQueryResult](workers[0], "LogCollector", easyCon.RouteQueryLogs, q)
+		if err != nil {
+			t.Fatalf("query %+v: %v", q, err)
+		}
+		return result
+	}
+	if result := query(easyCon.LogQuery{Module: "Worker*"}); len(result.Logs) != 4 || result.IsMore {
+		t.Errorf("all workers: %+v", result)
+	}
+	if result := query(easyCon.LogQuery{Module: "Worker1"}); len(result.Logs) != 2 {
+		t.Errorf("Worker1: %+v", result)
+	}
+	result := query(easyCon.LogQuery{Level: easyCon.ELogLevelWarning})
+	if len(result.Logs) != 2 || result.Logs[0].LogTime > result.Logs[1].LogTime {
+		t.Errorf("warning and above: %+v", result)
+	}
+	if result := query(easyCon.LogQuery{Text: "disk"}); len(result.Logs) != 1 || result.Logs[0].Error != "disk full" {
+		t.Errorf("text in error: %+v", result)
+	}
+	if result := query(easyCon.LogQuery{Text: "backup"}); len(result.Logs) != 1 || result.Logs[0].Fields["job"] != "backup" {
+		t.Errorf("text in fields: %+v", result)
+	}
+	if result := query(easyCon.LogQuery{Module: "Worker*", Limit: 1}); len(result.Logs) != 1 || !result.IsMore || result.Logs[0].Content != "worker2 slow" {
+		t.Errorf("limit keeps latest: %+v", result)
+	}
+	if result := query(easyCon.LogQuery{End: "2000-01-01"}); len(result.Logs) != 0 {
+		t.Errorf("time range: %+v", result)
+	}
+
+	// 重启后从文件重建索引
+	collector.Stop()
+	collector = newCgoCollector(t, &broker, easyCon.LogCollectorSetting{Dir: dir})
+	defer collector.Stop()
+	reloaded, err := collector.Query(easyCon.LogQuery{Module: "Worker2", Level: easyCon.ELogLevelInfo})
+	if err != nil || len(reloaded.Logs) != 2 {
+		t.Errorf("reloaded: %+v %v", reloaded, err)
+	}
+
+	// 重新连接后日志主题仍被订阅
+	collector.Adapter().Reset()
+	time.Sleep(time.Millisecond * 50)
+	workers[1].Info("after reset")
+	time.Sleep(time.Millisecond * 200)
+	if result, err := collector.Query(easyCon.LogQuery{Text: "after reset"}); err != nil || len(result.Logs) != 1 {
+		t.Errorf("after reset: %+v %v", result, err)
+	}
+}
+
+func TestLogCollectorRetention(t *testing.T) {
+	broker := easyCon.NewCgoBroker()
+	dir := t.TempDir()
+	collect := easyCon.LogCollectorSetting{Dir: dir, MaxSize: 8 << 10}
+	collector := newCgoCollector(t, &broker, collect)
+	setting := newCgoSetting("RetainWorker")
+	setting.LogMode = easyCon.ELogModeUpload
+	worker := newCgoModule(&broker, setting, easyCon.AdapterCallBack{})
+	defer worker.Stop()
+
+	const total = 200
+	for i := 0; i < total; i++ {
+		worker.Log(easyCon.ELogLevelInfo, "retain me", "i", i)
+	}
+	time.Sleep(time.Millisecond * 300)
+	// 超过总大小后整段删除最早的日志
+	segments, _ := filepath.Glob(filepath.Join(dir, "logs-*.jsonl"))
+	var size int64
+	for _, segment := range segments {
+		info, _ := os.Stat(segment)
+		size += info.Size()
+	}
+	if size > 10<<10 {
+		t.Errorf("store size %d over limit, %d segments", size, len(segments))
+	}
+	result, err := collector.Query(easyCon.LogQuery{Module: "RetainWorker", Limit: 1000})
+	if err != nil || len(result.Logs) == 0 || len(result.Logs) >= total {
+		t.Fatalf("kept %d logs: %v", len(result.Logs), err)
+	}
+	last := result.Logs[len(result.Logs)-1]
+	if last.Seq != total || last.Fields["i"] != float64(total-1) {
+		t.Fatalf("last log %+v", last)
+	}
+
+	// 重启后序号接着最大的序号递增，不因删除的分段重复
+	collector.Stop()
+	collector = newCgoCollector(t, &broker, collect)
+	defer collector.Stop()
+	worker.Info("after reload")
+	time.Sleep(time.Millisecond * 100)
+	result, err = collector.Query(easyCon.LogQuery{Text: "after reload"})
+	if err != nil || len(result.Logs) != 1 || result.Logs[0].Seq != total+1 {
+		t.Errorf("after reload: %+v %v", result, err)
+	}
+}
+
+func TestMqttLogCollectorPrefix(t *testing.T) {
+	addr := "ws://127.0.0.1:5002/ws"
+	if conn, err := net.DialTimeout("tcp", "127.0.0.1:5002", time.Millisecond*200); err != nil {
+		t.Skipf("no mqtt broker at %s", addr)
+	} else {
+		_ = conn.Close()
+	}
+	setting := easyCon.NewDefaultMqttSetting("PrefixCollector", addr)
+	setting.PreFix = "A."
+	collector, err := easyCon.NewMqttLogCollector(setting, easyCon.LogCollectorSetting{Dir: t.TempDir(), PreFixes: []string{"A."}}, easyCon.AdapterCallBack{})
+	if err != nil {
+		t.Fatalf("new collector: %v", err)
+	}
+	defer collector.Stop()
+
+	// 带前缀的模块直接上传的日志
+	workerSetting := easyCon.NewDefaultMqttSetting("PrefixWorker", addr)
+	workerSetting.PreFix = "A."
+	workerSetting.LogMode = easyCon.ELogModeUpload
+	worker := easyCon.NewMqttAdapter(workerSetting, easyCon.AdapterCallBack{})
+	defer worker.Stop()
+	time.Sleep(time.Millisecond * 500)
+	worker.Info("uploaded with prefix")
+	time.Sleep(time.Millisecond * 500)
+	result, err := collector.Query(easyCon.LogQuery{Module: "PrefixWorker"})
+	if err != nil || len(result.Logs) != 1 || result.Logs[0].PreFix != "A." {
+		t.Errorf("prefixed upload: %+v %v", result, err)
+	}
+}
@@ -0,0 +1,119 @@
+/**
+ * @Author: Joey
+ * @Description: 本地日志文件单元测试
+ * @Create Date: 2026/10/18 11:10
+ */
+
+package unitTest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	easyCon "github.com/qiu-tec/easy-con.golang"
+)
+
+// readLogFile 读取当前日志文件
+func readLogFile(t *testing.T, dir, module string) string {
+	raw, err := os.ReadFile(filepath.Join(dir, module+".log"))
+	if err != nil {
+		t.Fatalf("read log file: %v", err)
+	}
+	return string(raw)
+}
+
+func logBackups(dir, module string) []string {
+	backups, _ := filepath.Glob(filepath.Join(dir, module+"-*.log"))
+	return backups
+}
+
+func TestLogFileRotateBySize(t *testing.T) {
+	broker := easyCon.NewCgoBroker()
+	dir := t.TempDir()
+	setting := newCgoSetting("FileClient")
+	setting.LogMode = easyCon.ELogModeFile
+	setting.LogFile = easyCon.LogFileSetting{Dir: dir, MaxSize: 512, MaxBackups: 2}
+	client := newCgoModule(&broker, setting, easyCon.AdapterCallBack{})
+	defer client.Stop()
+
+	for i := 0; i < 50; i++ {
+		client.Log(easyCon.ELogLevelInfo, "rotate me", "i", i)
+		// 切分文件名精确到毫秒
+		time.Sleep(time.Millisecond * 2)
+	}
+	if n := len(logBackups(dir, "FileClient")); n != 2 {
+		t.Errorf("got %d backups, want 2", n)
+	}
+	content := readLogFile(t, dir, "FileClient")
+	if !strings.Contains(content, "[INFO][FileClient]: rotate me i=49") {
+		t.Errorf("last log missing: %s", content)
+	}
+	if info, _ := os.Stat(filepath.Join(dir, "FileClient.log")); info.Size() > 512 {
+		t.Errorf("file size %d over limit", info.Size())
+	}
+}
+
+func TestLogFileRotateByAge(t *testing.T) {
+	broker := easyCon.NewCgoBroker()
+	dir := t.TempDir()
+	setting := newCgoSetting("AgeClient")
+	setting.LogMode = easyCon.ELogModeFile
+	setting.LogFile = easyCon.LogFileSetting{Dir: dir, MaxAge: time.Millisecond * 50}
+	client := newCgoModule(&broker, setting, easyCon.AdapterCallBack{})
+	defer client.Stop()
+
+	client.Info("first")
+	time.Sleep(time.Millisecond * 80)
+	client.Info("second")
+	if n := len(logBackups(dir, "AgeClient")); n != 1 {
+		t.Fatalf("got %d backups, want 1", n)
+	}
+	if content := readLogFile(t, dir, "AgeClient"); strings.Contains(content, "first") || !strings.Contains(content, "second") {
+		t.Errorf("current file %s", content)
+	}
+}
+
+func TestLogFileWithUpload(t *testing.T) {
+	broker := easyCon.NewCgoBroker()
+	dir := t.TempDir()
+	setting := newCgoSetting("UploadFileClient")
+	setting.LogMode = easyCon.ELogModeUpload
+	setting.LogFile = easyCon.LogFileSetting{Dir: dir}
+	setting.FileLogLevel = easyCon.ELogLevelWarning
+	client := newCgoModule(&broker, setting, easyCon.AdapterCallBack{})
+
+	client.Info("uploaded only")
+	client.Warn("uploaded and filed")
+	client.Stop()
+	// 断线时上传不了的日志不受 FileLogLevel 限制
+	client.Info("offline")
+
+	content := readLogFile(t, dir, "UploadFileClient")
+	if strings.Contains(content, "uploaded only") {
+		t.Errorf("info filed under WARNING file level: %s", content)
+	}
+	if !strings.Contains(content, "uploaded and filed") || !strings.Contains(content, "offline") {
+		t.Errorf("log missing: %s", content)
+	}
+}
+
+func TestLogFileRotateSameMillisecond(t *testing.T) {
+	broker := easyCon.NewCgoBroker()
+	dir := t.TempDir()
+	setting := newCgoSetting("BurstClient")
+	setting.LogMode = easyCon.ELogModeFile
+	setting.LogFile = easyCon.LogFileSetting{Dir: dir, MaxSize: 1}
+	client := newCgoModule(&broker, setting, easyCon.AdapterCallBack{})
+	defer client.Stop()
+
+	// 每行都超过上限，连续写入时同一毫秒内多次切分，切分文件不能互相覆盖
+	for i := 0; i < 20; i++ {
+		client.Log(easyCon.ELogLevelInfo, "burst", "i", i)
+	}
+	if n := len(logBackups(dir, "BurstClient")); n != 19 {
+		t.Errorf("got %d backups, want 19", n)
+	}
+}
@@ -0,0 +1,95 @@
+/**
+ * @Author: Joey
+ * @Description: 日志级别、结构化字段与调用位置单元测试
+ * @Create Date: 2026/10/18 09:40
+ */
+
+package unitTest
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	easyCon "github.com/qiu-tec/easy-con.golang"
+)
+
+func TestLogPackFields(t *testing.T) {
+	broker := easyCon.NewCgoBroker()
+	var lock sync.Mutex
+	var logs []easyCon.PackLog
+	monitor, onRead := easyCon.NewCGoMonitorWithBroker(newCgoSetting("LogMonitor"), easyCon.AdapterCallBack{
+		OnLogRec: func(log easyCon.PackLog) {
+			if log.From != "LogClient" {
+				return
+			}
+			lock.Lock()
+			logs = append(logs, log)
+			lock.Unlock()
+		},
+	}, broker.Publish, broker.Publish)
+	defer monitor.Stop()
+	broker.RegClient("LogMonitor", onRead)
+	time.Sleep(time.Millisecond * 50)
+
+	setting := newCgoSetting("LogClient")
+	setting.LogMode = easyCon.ELogModeUpload
+	setting.UploadLogLevel = easyCon.ELogLevelInfo
+	client := newCgoModule(&broker, setting, easyCon.AdapterCallBack{})
+	defer client.Stop()
+
+	client.Trace("trace hidden")
+	client.Debug("debug hidden")
+	client.Info("info shown")
+	client.Err("nil error", nil) // 不应 panic
+	client.Err("with error", errors.New("boom"))
+	client.Log(easyCon.ELogLevelWarning, "structured", "user", "joey", "count", 3, "cause", errors.New("disk"), "odd")
+
+	time.Sleep(time.Millisecond * 200)
+	lock.Lock()
+	defer lock.Unlock()
+	if len(logs) != 4 {
+		t.Fatalf("got %d logs, want 4: %+v", len(logs), logs)
+	}
+	byContent := make(map[string]easyCon.PackLog)
+	for _, log := range logs {
+		byContent[log.Content] = log
+		if !strings.HasPrefix(log.Caller, "unitTest/logPack_test.go:") {
+			t.Errorf("caller %q", log.Caller)
+		}
+	}
+	if log := byContent["info shown"]; log.Level != easyCon.ELogLevelInfo {
+		t.Errorf("info level %s", log.Level)
+	}
+	if log := byContent["nil error"]; log.Level != easyCon.ELogLevelError || log.Error != "" {
+		t.Errorf("nil error log %+v", log)
+	}
+	if log := byContent["with error"]; log.Error != "boom" {
+		t.Errorf("error field %q", log.Error)
+	}
+	fields := byContent["structured"].Fields
+	if fields["user"] != "joey" || fields["count"] != float64(3) || fields["cause"] != "disk" || fields["!BADKEY"] != "odd" {
+		t.Errorf("fields %v", fields)
+	}
+}
+
+func TestSetLogLevelNewLevels(t *testing.T) {
+	broker := easyCon.NewCgoBroker()
+	setting := newCgoSetting("LevelServer")
+	setting.SysRouteAllow = []string{"LevelClient"}
+	server := newCgoModule(&broker, setting, easyCon.AdapterCallBack{})
+	defer server.Stop()
+	client := newCgoModule(&broker, newCgoSetting("LevelClient"), easyCon.AdapterCallBack{})
+	defer client.Stop()
+
+	for _, level := range []easyCon.ELogLevel{easyCon.ELogLevelTrace, easyCon.ELogLevelInfo} {
+		if resp := client.Req("LevelServer", easyCon.RouteSetLogLevel, []byte(level)); resp.RespCode != easyCon.ERespSuccess {
+			t.Errorf("set %s: %d %s", level, resp.RespCode, resp.Content)
+		}
+	}
+	if resp := client.Req("LevelServer", easyCon.RouteSetLogLevel, []byte("VERBOSE")); resp.RespCode != easyCon.ERespBadReq {
+		t.Errorf("unknown level accepted: %d", resp.RespCode)
+	}
+}
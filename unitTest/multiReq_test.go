@@ -0,0 +1,91 @@
+/**
+ * @Author: Joey
+ * @Description: 多模块请求与广播请求单元测试
+ * @Create Date: 2026/10/17 18:30
+ */
+
+package unitTest
+
+import (
+	"testing"
+	"time"
+
+	easyCon "github.com/qiu-tec/easy-con.golang"
+)
+
+func TestReqManyAndBroadcast(t *testing.T) {
+	broker := easyCon.NewCgoBroker()
+	client := newCgoModule(&broker, newCgoSetting("Commander"), easyCon.AdapterCallBack{})
+	for _, module := range []string{"Worker1", "Worker2", "Other"} {
+		m := module
+		_ = newCgoModule(&broker, newCgoSetting(m), easyCon.AdapterCallBack{
+			OnReqRec: func(pack easyCon.PackReq) (easyCon.EResp, []byte) {
+				return easyCon.ERespSuccess, []byte(m)
+			},
+		})
+	}
+
+	start := time.Now()
+	result := client.ReqMany([]string{"Worker1", "Worker2", "Dead"}, "Status", nil, 200)
+	if cost := time.Since(start); cost > time.Millisecond*400 {
+		t.Errorf("requests were not parallel, cost %v", cost)
+	}
+	if len(result) != 3 {
+		t.Fatalf("result size %d", len(result))
+	}
+	if string(result["Worker1"].Content) != "Worker1" || result["Worker2"].RespCode != easyCon.ERespSuccess {
+		t.Errorf("worker results %+v", result)
+	}
+	if result["Dead"].RespCode != easyCon.ERespTimeout {
+		t.Errorf("dead module code %d", result["Dead"].RespCode)
+	}
+	if len(result.Succeeded()) != 2 || len(result.Failed()) != 1 {
+		t.Errorf("succeeded %v failed %v", result.Succeeded(), result.Failed())
+	}
+
+	all := client.ReqBroadcast("*", "Status", nil, 200)
+	if len(all) != 3 {
+		t.Errorf("broadcast to all got %d responses", len(all))
+	}
+	for module, resp := range all {
+		if string(resp.Content) != module {
+			t.Errorf("broadcast resp from %s content %s", module, resp.Content)
+		}
+	}
+	workers := client.ReqBroadcast("Worker*", "Status", nil, 200)
+	if len(workers) != 2 {
+		t.Errorf("broadcast to workers got %v", workers)
+	}
+}
+
+func TestBroadcastInterceptor(t *testing.T) {
+	broker := easyCon.NewCgoBroker()
+	client := newCgoModule(&broker, newCgoSetting("BcCommander"), easyCon.AdapterCallBack{})
+	for _, module := range []string{"BcWorker1", "BcWorker2"} {
+		_ = newCgoModule(&broker, newCgoSetting(module), easyCon.AdapterCallBack{
+			OnReqRec: func(pack easyCon.PackReq) (easyCon.EResp, []byte) {
+				return easyCon.ERespSuccess, pack.Content
+			},
+		})
+	}
+	var summary easyCon.PackResp
+	client.Use(easyCon.Interceptor{
+		OnReq: func(pack easyCon.PackReq, next easyCon.ReqInvoker) easyCon.PackResp {
+			pack.Content = []byte("token")
+			summary = next(pack)
+			return summary
+		},
+	})
+
+	before := client.Stats().ReqSent
+	result := client.ReqBroadcast("BcWorker*", "Status", nil, 200)
+	if len(result) != 2 || string(result["BcWorker1"].Content) != "token" {
+		t.Errorf("broadcast did not pass interceptor: %+v", result)
+	}
+	if summary.RespCode != easyCon.ERespSuccess {
+		t.Errorf("summary code %d", summary.RespCode)
+	}
+	if n := client.Stats().ReqSent - before; n != 1 {
+		t.Errorf("broadcast counted %d sent", n)
+	}
+}
@@ -0,0 +1,89 @@
+/**
+ * @Author: Joey
+ * @Description: 按分组键保序处理单元测试
+ * @Create Date: 2026/10/18 18:20
+ */
+
+package unitTest
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	easyCon "github.com/qiu-tec/easy-con.golang"
+)
+
+func TestOrderedNotice(t *testing.T) {
+	const count = 30
+	broker := easyCon.NewCgoBroker()
+	var lock sync.Mutex
+	var got []int
+	done := make(chan struct{})
+	setting := newCgoSetting("OrderedListener")
+	setting.OrderKey = easyCon.EOrderFrom
+	listener := newCgoModule(&broker, setting, easyCon.AdapterCallBack{
+		OnNoticeRec: func(notice easyCon.PackNotice) {
+			n, _ := strconv.Atoi(string(notice.Content))
+			// 越早的消息处理越慢，无序处理时必然乱序
+			time.Sleep(time.Duration(count-n) * time.Millisecond)
+			lock.Lock()
+			got = append(got, n)
+			if len(got) == count {
+				close(done)
+			}
+			lock.Unlock()
+		},
+	})
+	listener.SubscribeNotice("Command", false)
+	time.Sleep(time.Millisecond * 50)
+
+	sender := newCgoModule(&broker, newCgoSetting("OrderedSender"), easyCon.AdapterCallBack{})
+	for i := 0; i < count; i++ {
+		if err := sender.SendNotice("Command", []byte(strconv.Itoa(i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	select {
+	case <-done:
+	case <-time.After(time.Second * 3):
+		t.Fatalf("received %d of %d notices", len(got), count)
+	}
+	for i, n := range got {
+		if n != i {
+			t.Fatalf("out of order: %v", got)
+		}
+	}
+}
+
+func TestOrderedKeysParallel(t *testing.T) {
+	broker := easyCon.NewCgoBroker()
+	setting := newCgoSetting("OrderedServer")
+	setting.OrderKey = easyCon.EOrderFrom
+	server := newCgoModule(&broker, setting, easyCon.AdapterCallBack{})
+	server.HandleFunc("Apply", func(easyCon.PackReq) (easyCon.EResp, []byte) {
+		time.Sleep(time.Millisecond * 100)
+		return easyCon.ERespSuccess, nil
+	})
+	clients := []easyCon.IAdapter{
+		newCgoModule(&broker, newCgoSetting("OrderedCtrlA"), easyCon.AdapterCallBack{}),
+		newCgoModule(&broker, newCgoSetting("OrderedCtrlB"), easyCon.AdapterCallBack{}),
+	}
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for _, client := range clients {
+		wg.Add(1)
+		go func(client easyCon.IAdapter) {
+			defer wg.Done()
+			if resp := client.Req("OrderedServer", "Apply", nil); resp.RespCode != easyCon.ERespSuccess {
+				t.Errorf("resp code %d", resp.RespCode)
+			}
+		}(client)
+	}
+	wg.Wait()
+	if cost := time.Since(start); cost > time.Millisecond*180 {
+		t.Errorf("different senders were serialized, cost %v", cost)
+	}
+}
@@ -0,0 +1,107 @@
+/**
+ * @Author: Joey
+ * @Description: 接收通道溢出策略单元测试
+ * @Create Date: 2026/10/18 17:50
+ */
+
+package unitTest
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	easyCon "github.com/qiu-tec/easy-con.golang"
+)
+
+func TestReqOverflowReject(t *testing.T) {
+	broker := easyCon.NewCgoBroker()
+	client := newCgoModule(&broker, newCgoSetting("OverflowClient"), easyCon.AdapterCallBack{})
+	setting := newCgoSetting("OverflowServer")
+	setting.ChannelBufferSize = 1
+	setting.ReqWorkers = 1
+	setting.ReqOverflow = easyCon.EOverflowReject
+	var overflowed int32
+	server := newCgoModule(&broker, setting, easyCon.AdapterCallBack{
+		OnOverflow: func(kind string, policy easyCon.EOverflowPolicy, pack easyCon.IPack) {
+			if kind == "Req" && policy == easyCon.EOverflowReject {
+				atomic.AddInt32(&overflowed, 1)
+			}
+		},
+	})
+	server.HandleFunc("Slow", func(easyCon.PackReq) (easyCon.EResp, []byte) {
+		time.Sleep(time.Millisecond * 100)
+		return easyCon.ERespSuccess, nil
+	})
+
+	var success, rejected int32
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			switch client.ReqWithTimeout("OverflowServer", "Slow", nil, 2000).RespCode {
+			case easyCon.ERespSuccess:
+				atomic.AddInt32(&success, 1)
+			case easyCon.ERespServiceUnavailable:
+				atomic.AddInt32(&rejected, 1)
+			}
+		}()
+	}
+	wg.Wait()
+	if success+rejected != 6 || rejected == 0 || success == 0 {
+		t.Fatalf("success %d rejected %d", success, rejected)
+	}
+	if n := atomic.LoadInt32(&overflowed); n != rejected {
+		t.Errorf("overflow callbacks %d, rejected %d", n, rejected)
+	}
+	if dropped := server.Stats().ReqDropped; dropped != uint64(rejected) {
+		t.Errorf("dropped counter %d, rejected %d", dropped, rejected)
+	}
+}
+
+func TestNoticeOverflowDropOldest(t *testing.T) {
+	broker := easyCon.NewCgoBroker()
+	sender := newCgoModule(&broker, newCgoSetting("DropOldestSender"), easyCon.AdapterCallBack{})
+	release := make(chan struct{})
+	var received int32
+	setting := newCgoSetting("DropOldestReceiver")
+	setting.ChannelBufferSize = 1
+	setting.NoticeWorkers = 1
+	setting.NoticeOverflow = easyCon.EOverflowDropOldest
+	receiver := newCgoModule(&broker, setting, easyCon.AdapterCallBack{
+		OnNoticeRec: func(easyCon.PackNotice) {
+			<-release
+			atomic.AddInt32(&received, 1)
+		},
+	})
+	receiver.SubscribeNotice("Burst", false)
+
+	// 处理协程阻塞时多个协程同时写入，写入不能阻塞
+	const total = 20
+	var wg sync.WaitGroup
+	for i := 0; i < total; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = sender.SendNotice("Burst", nil)
+		}()
+	}
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("senders blocked on a full channel")
+	}
+	close(release)
+	time.Sleep(time.Millisecond * 100)
+	dropped := receiver.Stats().NoticeDropped
+	if n := atomic.LoadInt32(&received); uint64(n)+dropped != total || dropped == 0 {
+		t.Errorf("received %d dropped %d", n, dropped)
+	}
+}
@@ -0,0 +1,104 @@
+/**
+ * @Author: Joey
+ * @Description: 模块在线状态单元测试
+ * @Create Date: 2026/10/18 15:40
+ */
+
+package unitTest
+
+import (
+	"testing"
+	"time"
+
+	easyCon "github.com/qiu-tec/easy-con.golang"
+)
+
+func TestPresence(t *testing.T) {
+	broker := easyCon.NewCgoBroker()
+	setting := newCgoSetting("Supervisor")
+	setting.IsTrackPresence = true
+	supervisor := newCgoModule(&broker, setting, easyCon.AdapterCallBack{})
+	events := make(chan bool, 10)
+	supervisor.WatchPresence(func(module string, online bool) {
+		if module == "PresenceWorker" {
+			events <- online
+		}
+	})
+
+	worker := newCgoModule(&broker, newCgoSetting("PresenceWorker"), easyCon.AdapterCallBack{})
+	select {
+	case online := <-events:
+		if !online {
+			t.Fatalf("want online event")
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("no online event")
+	}
+	if modules := supervisor.OnlineModules(); len(modules) != 1 || modules[0] != "PresenceWorker" {
+		t.Fatalf("online modules %v", modules)
+	}
+
+	go worker.Stop()
+	select {
+	case online := <-events:
+		if online {
+			t.Fatalf("want offline event")
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("no offline event")
+	}
+	if modules := supervisor.OnlineModules(); len(modules) != 0 {
+		t.Fatalf("online modules after stop %v", modules)
+	}
+}
+
+func TestFastFailOffline(t *testing.T) {
+	broker := easyCon.NewCgoBroker()
+	setting := newCgoSetting("FastFailCaller")
+	setting.IsFastFail = true
+	caller := newCgoModule(&broker, setting, easyCon.AdapterCallBack{})
+	defer caller.Stop()
+
+	offline := make(chan struct{})
+	caller.WatchPresence(func(module string, online bool) {
+		if module == "FastFailWorker" && !online {
+			close(offline)
+		}
+	})
+	worker := newCgoModule(&broker, newCgoSetting("FastFailWorker"), easyCon.AdapterCallBack{})
+	worker.HandleFunc("Echo", reply("pong"))
+	if resp := caller.Req("FastFailWorker", "Echo", nil); resp.RespCode != easyCon.ERespSuccess {
+		t.Fatalf("want success while online, got %d", resp.RespCode)
+	}
+
+	go worker.Stop()
+	select {
+	case <-offline:
+	case <-time.After(time.Second):
+		t.Fatalf("no offline event")
+	}
+	start := time.Now()
+	resp := caller.ReqWithTimeout("FastFailWorker", "Echo", nil, 2000)
+	if resp.RespCode != easyCon.ERespTargetOffline {
+		t.Fatalf("want target offline, got %d", resp.RespCode)
+	}
+	if cost := time.Since(start); cost > 100*time.Millisecond {
+		t.Fatalf("fast fail took %v", cost)
+	}
+	// 未知模块照常发送并等待超时
+	if resp = caller.ReqWithTimeout("UnknownModule", "Echo", nil, 100); resp.RespCode != easyCon.ERespTimeout {
+		t.Fatalf("want timeout for unknown module, got %d", resp.RespCode)
+	}
+	if stats := caller.Stats(); stats.ReqFastFail != 1 {
+		t.Fatalf("fast fail count %d", stats.ReqFastFail)
+	}
+
+	// 在线与已知离线的模块混合请求
+	other := newCgoModule(&broker, newCgoSetting("FastFailOther"), easyCon.AdapterCallBack{})
+	defer other.Stop()
+	other.HandleFunc("Echo", reply("pong"))
+	result := caller.ReqMany([]string{"FastFailOther", "FastFailWorker"}, "Echo", nil, 500)
+	if result["FastFailOther"].RespCode != easyCon.ERespSuccess || result["FastFailWorker"].RespCode != easyCon.ERespTargetOffline {
+		t.Fatalf("mixed ReqMany %+v", result)
+	}
+}
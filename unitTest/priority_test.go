@@ -0,0 +1,79 @@
+/**
+ * @Author: Joey
+ * @Description: 优先级通道单元测试
+ * @Create Date: 2026/10/18 20:30
+ */
+
+package unitTest
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	easyCon "github.com/qiu-tec/easy-con.golang"
+)
+
+func TestPriorityRoundTrip(t *testing.T) {
+	pack := easyCon.PackNotice{From: "A", Route: "EStop", Priority: easyCon.EPriorityHigh}
+	pack.PType = easyCon.EPTypeNotice
+	data, err := pack.Raw()
+	if err != nil {
+		t.Fatalf("Raw() failed: %v", err)
+	}
+	decoded, err := easyCon.UnmarshalPack(data)
+	if err != nil {
+		t.Fatalf("UnmarshalPack() failed: %v", err)
+	}
+	if notice := decoded.(*easyCon.PackNotice); notice.Priority != easyCon.EPriorityHigh {
+		t.Fatalf("priority lost: %+v", notice)
+	}
+}
+
+func TestPriorityLane(t *testing.T) {
+	const bulk = 20
+	broker := easyCon.NewCgoBroker()
+	var lock sync.Mutex
+	var order []string
+	done := make(chan struct{})
+	setting := newCgoSetting("PriorityListener")
+	setting.IsSync = true
+	listener := newCgoModule(&broker, setting, easyCon.AdapterCallBack{
+		OnNoticeRec: func(notice easyCon.PackNotice) {
+			time.Sleep(time.Millisecond * 10)
+			lock.Lock()
+			order = append(order, notice.Route)
+			if len(order) == bulk+1 {
+				close(done)
+			}
+			lock.Unlock()
+		},
+	})
+	listener.SubscribeNotice("Telemetry", false)
+	listener.SubscribeNotice("EStop", false)
+	time.Sleep(time.Millisecond * 50)
+
+	// 发送方按路由设置优先级
+	senderSetting := newCgoSetting("PrioritySender")
+	senderSetting.RoutePriority = map[string]easyCon.EPriority{"EStop": easyCon.EPriorityHigh}
+	sender := newCgoModule(&broker, senderSetting, easyCon.AdapterCallBack{})
+	for i := 0; i < bulk; i++ {
+		_ = sender.SendNotice("Telemetry", nil)
+	}
+	_ = sender.SendNotice("EStop", nil)
+
+	select {
+	case <-done:
+	case <-time.After(time.Second * 3):
+		t.Fatalf("received %d notices", len(order))
+	}
+	for i, route := range order {
+		if route == "EStop" {
+			if i > 3 {
+				t.Fatalf("EStop handled at %d behind bulk traffic: %v", i, order)
+			}
+			return
+		}
+	}
+	t.Fatalf("EStop not received: %v", order)
+}
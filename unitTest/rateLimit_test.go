@@ -0,0 +1,89 @@
+/**
+ * @Author: Joey
+ * @Description: 令牌桶限流单元测试
+ * @Create Date: 2026/10/18 19:30
+ */
+
+package unitTest
+
+import (
+	"encoding/json"
+	"testing"
+
+	easyCon "github.com/qiu-tec/easy-con.golang"
+)
+
+// countCodes 顺序发出 n 个请求，统计成功与限流数
+func countCodes(client easyCon.IAdapter, module, route string, n int) (success, limited int) {
+	for i := 0; i < n; i++ {
+		switch client.Req(module, route, nil).RespCode {
+		case easyCon.ERespSuccess:
+			success++
+		case easyCon.ERespTooManyRequests:
+			limited++
+		}
+	}
+	return
+}
+
+func TestRateLimit(t *testing.T) {
+	broker := easyCon.NewCgoBroker()
+	setting := newCgoSetting("LimitedServer")
+	setting.RateLimit = easyCon.RateLimitSetting{
+		Callers: map[string]easyCon.RateLimit{easyCon.AnyKey: {Rate: 1, Burst: 3}},
+	}
+	setting.SysRouteAllow = []string{"LimitAdmin"}
+	server := newCgoModule(&broker, setting, easyCon.AdapterCallBack{})
+	server.HandleFunc("Query", reply("ok"))
+	noisy := newCgoModule(&broker, newCgoSetting("NoisyCaller"), easyCon.AdapterCallBack{})
+	quiet := newCgoModule(&broker, newCgoSetting("QuietCaller"), easyCon.AdapterCallBack{})
+	admin := newCgoModule(&broker, newCgoSetting("LimitAdmin"), easyCon.AdapterCallBack{})
+
+	// 每个调用模块各自一个桶
+	if success, limited := countCodes(noisy, "LimitedServer", "Query", 5); success != 3 || limited != 2 {
+		t.Fatalf("noisy caller success %d limited %d", success, limited)
+	}
+	if success, _ := countCodes(quiet, "LimitedServer", "Query", 2); success != 2 {
+		t.Fatalf("quiet caller was limited by noisy caller")
+	}
+	// 系统路由不受限
+	if resp := noisy.Req("LimitedServer", easyCon.RoutePing, nil); resp.RespCode != easyCon.ERespSuccess {
+		t.Fatalf("ping was limited: %d", resp.RespCode)
+	}
+	if stats := server.Stats(); stats.ReqLimited != 2 {
+		t.Fatalf("limited counter %d", stats.ReqLimited)
+	}
+
+	// 运行时改为按路由限流
+	content, _ := json.Marshal(easyCon.RateLimitSetting{
+		Routes: map[string]easyCon.RateLimit{"Query": {Rate: 1000}},
+	})
+	if resp := noisy.Req("LimitedServer", easyCon.RouteSetRateLimit, content); resp.RespCode != easyCon.ERespForbidden {
+		t.Fatalf("set rate limit from caller: %d", resp.RespCode)
+	}
+	resp := admin.Req("LimitedServer", easyCon.RouteSetRateLimit, content)
+	var current easyCon.RateLimitSetting
+	if err := json.Unmarshal(resp.Content, &current); err != nil || current.Routes["Query"].Rate != 1000 || len(current.Callers) != 0 {
+		t.Fatalf("set rate limit: %d %s", resp.RespCode, resp.Content)
+	}
+	if success, _ := countCodes(noisy, "LimitedServer", "Query", 5); success != 5 {
+		t.Fatalf("noisy caller still limited after update, success %d", success)
+	}
+}
+
+func TestRateLimitDisabledSysRoute(t *testing.T) {
+	broker := easyCon.NewCgoBroker()
+	setting := newCgoSetting("NoSysServer")
+	setting.IsDisableSysRoute = true
+	setting.RateLimit = easyCon.RateLimitSetting{
+		Callers: map[string]easyCon.RateLimit{easyCon.AnyKey: {Rate: 1, Burst: 2}},
+	}
+	server := newCgoModule(&broker, setting, easyCon.AdapterCallBack{})
+	server.HandleFunc(easyCon.RoutePing, reply("user ping"))
+	client := newCgoModule(&broker, newCgoSetting("NoSysCaller"), easyCon.AdapterCallBack{})
+
+	// 禁用系统路由后，同名的用户路由照常限流
+	if success, limited := countCodes(client, "NoSysServer", easyCon.RoutePing, 4); success != 2 || limited != 2 {
+		t.Fatalf("user ping success %d limited %d", success, limited)
+	}
+}
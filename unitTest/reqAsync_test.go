@@ -0,0 +1,88 @@
+/**
+ * @Author: Joey
+ * @Description: ReqAsync 单元测试
+ * @Create Date: 2026/10/17 11:20
+ */
+
+package unitTest
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	easyCon "github.com/qiu-tec/easy-con.golang"
+)
+
+func TestReqAsync(t *testing.T) {
+	broker := easyCon.NewCgoBroker()
+	client := newCgoModule(&broker, newCgoSetting("AsyncClient"), easyCon.AdapterCallBack{})
+	_ = newCgoModule(&broker, newCgoSetting("AsyncServer"), easyCon.AdapterCallBack{
+		OnReqRec: func(pack easyCon.PackReq) (easyCon.EResp, []byte) {
+			time.Sleep(time.Millisecond * 100)
+			return easyCon.ERespSuccess, pack.Content
+		},
+	})
+
+	const n = 50
+	futures := make([]*easyCon.ReqFuture, n)
+	start := time.Now()
+	for i := 0; i < n; i++ {
+		futures[i] = client.ReqAsync("AsyncServer", "Echo", []byte(fmt.Sprint(i)))
+	}
+	var called int32
+	futures[0].Then(func(easyCon.PackResp) {
+		atomic.AddInt32(&called, 1)
+	})
+	for i, f := range futures {
+		resp := f.Wait()
+		if resp.RespCode != easyCon.ERespSuccess || string(resp.Content) != fmt.Sprint(i) {
+			t.Fatalf("future %d: code=%d content=%s", i, resp.RespCode, resp.Content)
+		}
+	}
+	if cost := time.Since(start); cost > time.Second {
+		t.Errorf("requests were not concurrent, cost %v", cost)
+	}
+	// 已完成的future注册回调应立即执行
+	futures[1].Then(func(easyCon.PackResp) {
+		atomic.AddInt32(&called, 1)
+	})
+	if atomic.LoadInt32(&called) != 2 {
+		t.Errorf("callbacks called %d times", called)
+	}
+}
+
+func TestReqAsyncCancel(t *testing.T) {
+	broker := easyCon.NewCgoBroker()
+	client := newCgoModule(&broker, newCgoSetting("AsyncCancelClient"), easyCon.AdapterCallBack{})
+	server := newCgoModule(&broker, newCgoSetting("AsyncCancelServer"), easyCon.AdapterCallBack{})
+	server.HandleFunc("Slow", func(pack easyCon.PackReq) (easyCon.EResp, []byte) {
+		time.Sleep(time.Second * 2)
+		return easyCon.ERespSuccess, nil
+	})
+
+	// WaitCtx 超时后请求随即放弃，不再等待 TimeOut×重试次数
+	future := client.ReqAsync("AsyncCancelServer", "Slow", nil)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+	defer cancel()
+	if _, err := future.WaitCtx(ctx); err == nil {
+		t.Fatalf("WaitCtx should time out")
+	}
+	start := time.Now()
+	if resp := future.Wait(); resp.RespCode != easyCon.ERespTimeout {
+		t.Errorf("canceled future code %d", resp.RespCode)
+	}
+	if cost := time.Since(start); cost > time.Millisecond*200 {
+		t.Errorf("request kept running %v after WaitCtx timeout", cost)
+	}
+
+	future = client.ReqAsync("AsyncCancelServer", "Slow", nil)
+	future.Cancel()
+	select {
+	case <-future.Done():
+	case <-time.After(time.Millisecond * 200):
+		t.Errorf("Cancel did not finish the future")
+	}
+}
@@ -0,0 +1,55 @@
+/**
+ * @Author: Joey
+ * @Description: ReqCtx 单元测试
+ * @Create Date: 2026/10/17 10:20
+ */
+
+package unitTest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	easyCon "github.com/qiu-tec/easy-con.golang"
+)
+
+func TestReqCtx(t *testing.T) {
+	broker := easyCon.NewCgoBroker()
+	client := newCgoModule(&broker, newCgoSetting("CtxClient"), easyCon.AdapterCallBack{})
+	_ = newCgoModule(&broker, newCgoSetting("CtxServer"), easyCon.AdapterCallBack{
+		OnReqRec: func(pack easyCon.PackReq) (easyCon.EResp, []byte) {
+			if pack.Route == "Slow" {
+				time.Sleep(time.Second * 2)
+			}
+			return easyCon.ERespSuccess, pack.Content
+		},
+	})
+
+	resp, err := client.ReqCtx(context.Background(), "CtxServer", "Echo", []byte("hi"))
+	if err != nil || resp.RespCode != easyCon.ERespSuccess || string(resp.Content) != "hi" {
+		t.Fatalf("echo failed: code=%d err=%v", resp.RespCode, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*200)
+	defer cancel()
+	start := time.Now()
+	resp, err = client.ReqCtx(ctx, "CtxServer", "Slow", nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("want DeadlineExceeded, got %v", err)
+	}
+	if resp.RespCode != easyCon.ERespTimeout {
+		t.Errorf("want timeout code, got %d", resp.RespCode)
+	}
+	if cost := time.Since(start); cost > time.Millisecond*500 {
+		t.Errorf("deadline not respected, cost %v", cost)
+	}
+
+	ctx2, cancel2 := context.WithCancel(context.Background())
+	time.AfterFunc(time.Millisecond*100, cancel2)
+	_, err = client.ReqCtx(ctx2, "CtxServer", "Slow", nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("want Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,67 @@
+/**
+ * @Author: Joey
+ * @Description: 重试策略单元测试
+ * @Create Date: 2026/10/17 17:30
+ */
+
+package unitTest
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	easyCon "github.com/qiu-tec/easy-con.golang"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	broker := easyCon.NewCgoBroker()
+	setting := newCgoSetting("RetryClient")
+	setting.Retry = &easyCon.RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   time.Millisecond * 50,
+		Multiplier:  2,
+		RetryOn:     []easyCon.EResp{easyCon.ERespTimeout, easyCon.ERespError},
+		Routes: map[string]easyCon.RetryPolicy{
+			"Charge": easyCon.NoRetry,
+		},
+	}
+	client := newCgoModule(&broker, setting, easyCon.AdapterCallBack{})
+	var flaky, charge int32
+	_ = newCgoModule(&broker, newCgoSetting("RetryServer"), easyCon.AdapterCallBack{
+		OnReqRec: func(pack easyCon.PackReq) (easyCon.EResp, []byte) {
+			switch pack.Route {
+			case "Flaky":
+				if atomic.AddInt32(&flaky, 1) < 3 {
+					return easyCon.ERespError, nil
+				}
+				return easyCon.ERespSuccess, nil
+			case "Charge":
+				atomic.AddInt32(&charge, 1)
+				return easyCon.ERespError, nil
+			}
+			return easyCon.ERespRouteNotFind, nil
+		},
+	})
+
+	start := time.Now()
+	resp := client.Req("RetryServer", "Flaky", nil)
+	if resp.RespCode != easyCon.ERespSuccess || atomic.LoadInt32(&flaky) != 3 {
+		t.Fatalf("flaky: code=%d attempts=%d", resp.RespCode, flaky)
+	}
+	// 50ms + 100ms 退避
+	if cost := time.Since(start); cost < time.Millisecond*150 {
+		t.Errorf("backoff not applied, cost %v", cost)
+	}
+
+	resp = client.Req("RetryServer", "Charge", nil)
+	if resp.RespCode != easyCon.ERespError || atomic.LoadInt32(&charge) != 1 {
+		t.Fatalf("charge: code=%d attempts=%d", resp.RespCode, charge)
+	}
+
+	// 不可重试的响应码直接返回
+	resp = client.Req("RetryServer", "Unknown", nil)
+	if resp.RespCode != easyCon.ERespRouteNotFind {
+		t.Fatalf("unknown: code=%d", resp.RespCode)
+	}
+}
@@ -0,0 +1,72 @@
+/**
+ * @Author: Joey
+ * @Description: 路由器单元测试
+ * @Create Date: 2026/10/17 14:05
+ */
+
+package unitTest
+
+import (
+	"testing"
+
+	easyCon "github.com/qiu-tec/easy-con.golang"
+)
+
+func reply(content string) easyCon.ReqHandler {
+	return func(easyCon.PackReq) (easyCon.EResp, []byte) {
+		return easyCon.ERespSuccess, []byte(content)
+	}
+}
+
+func TestRouterMatch(t *testing.T) {
+	router := easyCon.NewRouter()
+	router.HandleFunc("user/get", reply("exact"))
+	router.HandleFunc("user/*", reply("user"))
+	router.HandleFunc("user/admin/*", reply("admin"))
+	router.HandleFunc("*", reply("all"))
+
+	cases := map[string]string{
+		"user/get":        "exact",
+		"user/set":        "user",
+		"user/admin/del":  "admin",
+		"order/create":    "all",
+		"user/admin":      "user",
+		"user/admin/x/yy": "admin",
+	}
+	for route, want := range cases {
+		code, content := router.ServeReq(easyCon.PackReq{Route: route})
+		if code != easyCon.ERespSuccess || string(content) != want {
+			t.Errorf("route %s: got %d %s, want %s", route, code, content, want)
+		}
+	}
+	if n := len(router.Routes()); n != 4 {
+		t.Errorf("routes count %d", n)
+	}
+
+	empty := easyCon.NewRouter()
+	if code, _ := empty.ServeReq(easyCon.PackReq{Route: "x"}); code != easyCon.ERespRouteNotFind {
+		t.Errorf("want route not find, got %d", code)
+	}
+}
+
+func TestAdapterHandle(t *testing.T) {
+	broker := easyCon.NewCgoBroker()
+	client := newCgoModule(&broker, newCgoSetting("RouteClient"), easyCon.AdapterCallBack{})
+	server := newCgoModule(&broker, newCgoSetting("RouteServer"), easyCon.AdapterCallBack{})
+	// 连接后再注册路由，需要自动补订阅请求主题
+	server.HandleFunc("hello", reply("world"))
+	server.Handle("user/*", reply("user"))
+
+	resp := client.Req("RouteServer", "hello", nil)
+	if resp.RespCode != easyCon.ERespSuccess || string(resp.Content) != "world" {
+		t.Fatalf("hello: %d %s", resp.RespCode, resp.Content)
+	}
+	resp = client.Req("RouteServer", "user/get", nil)
+	if string(resp.Content) != "user" {
+		t.Fatalf("user/get: %d %s", resp.RespCode, resp.Content)
+	}
+	resp = client.Req("RouteServer", "unknown", nil)
+	if resp.RespCode != easyCon.ERespRouteNotFind {
+		t.Fatalf("unknown: %d", resp.RespCode)
+	}
+}
@@ -0,0 +1,119 @@
+/**
+ * @Author: Joey
+ * @Description: 系统路由单元测试
+ * @Create Date: 2026/10/18 14:00
+ */
+
+package unitTest
+
+import (
+	"encoding/json"
+	"testing"
+
+	easyCon "github.com/qiu-tec/easy-con.golang"
+)
+
+func TestSysRoutes(t *testing.T) {
+	broker := easyCon.NewCgoBroker()
+	client := newCgoModule(&broker, newCgoSetting("SysClient"), easyCon.AdapterCallBack{})
+	setting := newCgoSetting("SysServer")
+	setting.SysRouteAllow = []string{"Admin"}
+	server := newCgoModule(&broker, setting, easyCon.AdapterCallBack{
+		OnGetVersion: func() []string {
+			return []string{"SysServer:1.0.0"}
+		},
+	})
+	server.HandleFunc("biz", reply("ok"))
+
+	// 没有注册任何路由与 OnReqRec 的模块同样响应系统路由
+	resp := server.Req("SysClient", easyCon.RoutePing, []byte("hi"))
+	if resp.RespCode != easyCon.ERespSuccess || string(resp.Content) != "hi" {
+		t.Fatalf("ping: %d %s", resp.RespCode, resp.Content)
+	}
+
+	resp = client.Req("SysServer", easyCon.RouteGetVersion, nil)
+	var versions []string
+	if err := json.Unmarshal(resp.Content, &versions); err != nil || len(versions) != 2 || versions[1] != "SysServer:1.0.0" {
+		t.Fatalf("versions: %v %s", err, resp.Content)
+	}
+
+	resp = client.Req("SysServer", easyCon.RouteGetRoutes, nil)
+	var routes easyCon.RoutesInfo
+	if err := json.Unmarshal(resp.Content, &routes); err != nil || len(routes.Routes) != 1 || routes.Routes[0] != "biz" {
+		t.Fatalf("routes: %v %s", err, resp.Content)
+	}
+
+	_ = client.Req("SysServer", "biz", nil)
+	resp = client.Req("SysServer", easyCon.RouteGetStats, nil)
+	var stats easyCon.Stats
+	if err := json.Unmarshal(resp.Content, &stats); err != nil || stats.ReqRec < 4 || stats.Module != "SysServer" {
+		t.Fatalf("stats: %v %s", err, resp.Content)
+	}
+
+	// 受保护的路由
+	resp = client.Req("SysServer", easyCon.RouteSetLogLevel, []byte(easyCon.ELogLevelError))
+	if resp.RespCode != easyCon.ERespForbidden {
+		t.Fatalf("set log level from SysClient: %d", resp.RespCode)
+	}
+	admin := newCgoModule(&broker, newCgoSetting("Admin"), easyCon.AdapterCallBack{})
+	resp = admin.Req("SysServer", easyCon.RouteSetLogLevel, []byte(easyCon.ELogLevelError))
+	if resp.RespCode != easyCon.ERespSuccess {
+		t.Fatalf("set log level from Admin: %d", resp.RespCode)
+	}
+	resp = admin.Req("SysServer", easyCon.RouteGetConfig, nil)
+	var config easyCon.CoreSetting
+	if err := json.Unmarshal(resp.Content, &config); err != nil || config.LogLevel != easyCon.ELogLevelError {
+		t.Fatalf("config: %v %s", err, resp.Content)
+	}
+
+	// 禁用系统路由
+	disabled := newCgoSetting("NoSys")
+	disabled.IsDisableSysRoute = true
+	_ = newCgoModule(&broker, disabled, easyCon.AdapterCallBack{
+		OnReqRec: func(pack easyCon.PackReq) (easyCon.EResp, []byte) {
+			return easyCon.ERespRouteNotFind, nil
+		},
+	})
+	resp = client.Req("NoSys", easyCon.RoutePing, nil)
+	if resp.RespCode != easyCon.ERespRouteNotFind {
+		t.Fatalf("disabled ping: %d", resp.RespCode)
+	}
+}
+
+func TestSysRouteDefaultsAndOverride(t *testing.T) {
+	broker := easyCon.NewCgoBroker()
+	client := newCgoModule(&broker, newCgoSetting("DefaultClient"), easyCon.AdapterCallBack{})
+	defer client.Stop()
+	exited := make(chan struct{}, 1)
+	server := newCgoModule(&broker, newCgoSetting("DefaultServer"), easyCon.AdapterCallBack{
+		OnExiting: func() { exited <- struct{}{} },
+	})
+	defer server.Stop()
+
+	// 未设置 SysRouteAllow 时受保护的路由全部拒绝
+	for _, route := range []string{easyCon.RouteExit, easyCon.RouteGetConfig, easyCon.RouteSetLogLevel, easyCon.RouteSetRateLimit} {
+		if resp := client.Req("DefaultServer", route, nil); resp.RespCode != easyCon.ERespForbidden {
+			t.Errorf("%s allowed by default: %d", route, resp.RespCode)
+		}
+	}
+	select {
+	case <-exited:
+		t.Fatalf("exit ran without permission")
+	default:
+	}
+
+	// 注册的同名路由优先于系统路由
+	server.HandleFunc(easyCon.RoutePing, reply("user ping"))
+	if resp := client.Req("DefaultServer", easyCon.RoutePing, []byte("hi")); string(resp.Content) != "user ping" {
+		t.Errorf("user ping shadowed: %d %s", resp.RespCode, resp.Content)
+	}
+
+	// * 允许全部模块
+	setting := newCgoSetting("OpenServer")
+	setting.SysRouteAllow = []string{"*"}
+	open := newCgoModule(&broker, setting, easyCon.AdapterCallBack{})
+	defer open.Stop()
+	if resp := client.Req("OpenServer", easyCon.RouteGetConfig, nil); resp.RespCode != easyCon.ERespSuccess {
+		t.Errorf("get config with * allow: %d", resp.RespCode)
+	}
+}
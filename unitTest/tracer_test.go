@@ -0,0 +1,91 @@
+/**
+ * @Author: Joey
+ * @Description: 内部运行日志单元测试
+ * @Create Date: 2026/10/18 23:30
+ */
+
+package unitTest
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+
+	easyCon "github.com/qiu-tec/easy-con.golang"
+)
+
+// syncBuffer 并发安全的日志缓冲
+type syncBuffer struct {
+	lock sync.Mutex
+	buf  bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.buf.String()
+}
+
+func TestTraceScopes(t *testing.T) {
+	broker := easyCon.NewCgoBroker()
+	var traced, quiet syncBuffer
+	debug := &slog.HandlerOptions{Level: slog.LevelDebug}
+
+	setting := newCgoSetting("TraceClient")
+	setting.Logger = easyCon.NewSlogLogger(slog.New(slog.NewTextHandler(&traced, debug)), setting.Module)
+	setting.TraceScopes = []easyCon.ETraceScope{easyCon.ETraceCore}
+	client := newCgoModule(&broker, setting, easyCon.AdapterCallBack{})
+
+	serverSetting := newCgoSetting("TraceServer")
+	serverSetting.Logger = easyCon.NewSlogLogger(slog.New(slog.NewTextHandler(&quiet, debug)), serverSetting.Module)
+	server := newCgoModule(&broker, serverSetting, easyCon.AdapterCallBack{})
+	server.HandleFunc("Echo", reply("pong"))
+
+	if resp := client.Req("TraceServer", "Echo", nil); resp.RespCode != easyCon.ERespSuccess {
+		t.Fatalf("resp code %d", resp.RespCode)
+	}
+	out := traced.String()
+	if !strings.Contains(out, "send request") || !strings.Contains(out, "module=TraceClient") || !strings.Contains(out, "scope=CORE") {
+		t.Errorf("core trace missing: %s", out)
+	}
+	if strings.Contains(out, "scope=CGO") {
+		t.Errorf("cgo trace not enabled but logged: %s", out)
+	}
+	if out := quiet.String(); out != "" {
+		t.Errorf("tracing off but logged: %s", out)
+	}
+}
+
+func TestBrokerSetLoggerWhileDelivering(t *testing.T) {
+	broker := easyCon.NewCgoBroker()
+	server := newCgoModule(&broker, newCgoSetting("BrokerLogServer"), easyCon.AdapterCallBack{})
+	defer server.Stop()
+	server.HandleFunc("Echo", reply("pong"))
+	client := newCgoModule(&broker, newCgoSetting("BrokerLogClient"), easyCon.AdapterCallBack{})
+	defer client.Stop()
+
+	// 转发时替换日志，-race 下不应报告数据竞争
+	var out syncBuffer
+	logger := easyCon.NewSlogLogger(slog.New(slog.NewTextHandler(&out, &slog.HandlerOptions{Level: slog.LevelDebug})), "Broker")
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 50; i++ {
+			broker.SetLogger(logger, i%2 == 0)
+		}
+	}()
+	for i := 0; i < 20; i++ {
+		if resp := client.Req("BrokerLogServer", "Echo", nil); resp.RespCode != easyCon.ERespSuccess {
+			t.Fatalf("resp code %d", resp.RespCode)
+		}
+	}
+	<-done
+}
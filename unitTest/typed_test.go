@@ -0,0 +1,74 @@
+/**
+ * @Author: Joey
+ * @Description: 类型化请求与编解码器单元测试
+ * @Create Date: 2026/10/17 16:00
+ */
+
+package unitTest
+
+import (
+	"errors"
+	"testing"
+
+	easyCon "github.com/qiu-tec/easy-con.golang"
+)
+
+type addReq struct {
+	A, B int
+}
+
+type addResp struct {
+	Sum int
+}
+
+func TestCodec(t *testing.T) {
+	for name, codec := range map[string]easyCon.Codec{"json": easyCon.JsonCodec, "gob": easyCon.GobCodec} {
+		data, err := codec.Marshal(addReq{A: 1, B: 2})
+		if err != nil {
+			t.Fatalf("%s marshal: %v", name, err)
+		}
+		var req addReq
+		if err = codec.Unmarshal(data, &req); err != nil || req.A != 1 || req.B != 2 {
+			t.Fatalf("%s unmarshal: %v %+v", name, err, req)
+		}
+	}
+	data, err := easyCon.RawCodec.Marshal("raw")
+	if err != nil || string(data) != "raw" {
+		t.Fatalf("raw marshal: %v %s", err, data)
+	}
+	var s string
+	if err = easyCon.RawCodec.Unmarshal(data, &s); err != nil || s != "raw" {
+		t.Fatalf("raw unmarshal: %v %s", err, s)
+	}
+	if _, err = easyCon.RawCodec.Marshal(addReq{}); err == nil {
+		t.Errorf("raw codec should reject struct")
+	}
+}
+
+func TestCallTyped(t *testing.T) {
+	broker := easyCon.NewCgoBroker()
+	client := newCgoModule(&broker, newCgoSetting("TypedClient"), easyCon.AdapterCallBack{})
+	server := newCgoModule(&broker, newCgoSetting("TypedServer"), easyCon.AdapterCallBack{})
+	easyCon.HandleTyped(server, "add", func(_ easyCon.PackReq, req addReq) (easyCon.EResp, addResp) {
+		return easyCon.ERespSuccess, addResp{Sum: req.A + req.B}
+	})
+	easyCon.HandleTypedWithCodec(server, easyCon.GobCodec, "addGob", func(_ easyCon.PackReq, req addReq) (easyCon.EResp, addResp) {
+		return easyCon.ERespSuccess, addResp{Sum: req.A + req.B}
+	})
+
+	resp, err := easyCon.Call[addReq, addResp](client, "TypedServer", "add", addReq{A: 3, B: 4})
+	if err != nil || resp.Sum != 7 {
+		t.Fatalf("add: %v %+v", err, resp)
+	}
+	resp, err = easyCon.CallWithCodec[addReq, addResp](client, easyCon.GobCodec, "TypedServer", "addGob", addReq{A: 5, B: 6})
+	if err != nil || resp.Sum != 11 {
+		t.Fatalf("addGob: %v %+v", err, resp)
+	}
+
+	// 类型不匹配在服务端解码时即报错
+	_, err = easyCon.Call[string, addResp](client, "TypedServer", "add", "not an object")
+	var respErr *easyCon.RespError
+	if !errors.As(err, &respErr) || respErr.Code != easyCon.ERespBadReq {
+		t.Fatalf("want bad req error, got %v", err)
+	}
+}
@@ -1,6 +1,6 @@
 module versionAndExit
 
-go 1.20
+go 1.21
 
 require github.com/qiu-tec/easy-con.golang v0.2.1
 
@@ -8,5 +8,7 @@ require (
 	github.com/eclipse/paho.mqtt.golang v1.4.3 // indirect
 	github.com/gorilla/websocket v1.5.3 // indirect
 	golang.org/x/net v0.27.0 // indirect
-	golang.org/x/sync v0.1.0 // indirect
+	golang.org/x/sync v0.7.0 // indirect
 )
+
+replace github.com/qiu-tec/easy-con.golang => ../../
@@ -23,26 +23,31 @@ func getVersion() string {
 }
 func main() {
 	fmt.Println(getVersion())
-	setting := easyCon.NewSetting("Commander", "ws://127.0.0.1:5002/ws", nil, nil)
-	cmd := easyCon.NewMqttAdapter(setting)
+	setting := easyCon.NewDefaultMqttSetting("Commander", "ws://127.0.0.1:5002/ws")
+	cmd := easyCon.NewMqttAdapter(setting, easyCon.AdapterCallBack{})
 
 	setting.Module = "Worker"
-	setting.OnGetVersion = func() []string {
-		return []string{"Worker:" + getVersion()}
-	}
-	setting.OnExiting = func() {
-		fmt.Println("exiting")
-		time.Sleep(time.Second)
-		fmt.Println("ready to exit")
-	}
-
-	easyCon.NewMqttAdapter(setting)
+	setting.SysRouteAllow = []string{"Commander"}
+	easyCon.NewMqttAdapter(setting, easyCon.AdapterCallBack{
+		OnGetVersion: func() []string {
+			return []string{"Worker:" + getVersion()}
+		},
+		OnExiting: func() {
+			fmt.Println("exiting")
+			time.Sleep(time.Second)
+			fmt.Println("ready to exit")
+		},
+	})
 
-	res := cmd.Req("Worker", "GetVersion", nil)
-	fmt.Println(res)
+	res := cmd.Req("Worker", easyCon.RouteGetVersion, nil)
+	fmt.Println(res.RespCode, string(res.Content))
+	res = cmd.Req("Worker", easyCon.RoutePing, []byte("hello"))
+	fmt.Println(res.RespCode, string(res.Content))
+	res = cmd.Req("Worker", easyCon.RouteGetStats, nil)
+	fmt.Println(res.RespCode, string(res.Content))
 	time.Sleep(time.Second)
-	res = cmd.Req("Worker", "Exit", nil)
-	fmt.Println(res)
+	res = cmd.Req("Worker", easyCon.RouteExit, nil)
+	fmt.Println(res.RespCode, string(res.Content))
 	time.Sleep(time.Second)
 	cmd.Stop()
 }
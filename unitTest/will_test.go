@@ -0,0 +1,207 @@
+/**
+ * @Author: Joey
+ * @Description: MQTT 遗嘱单元测试
+ * @Create Date: 2026/10/18 16:00
+ */
+
+package unitTest
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	easyCon "github.com/qiu-tec/easy-con.golang"
+)
+
+// mqttWill CONNECT 报文中的遗嘱
+type mqttWill struct {
+	Enabled bool
+	Topic   string
+	Payload []byte
+	Qos     byte
+	Retain  bool
+}
+
+// startWillBroker 只接受连接的 MQTT 服务端，记录每次连接的遗嘱
+func startWillBroker(t *testing.T) (string, <-chan mqttWill) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+	wills := make(chan mqttWill, 10)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				will, err := readConnectWill(bufio.NewReader(conn))
+				if err != nil {
+					return
+				}
+				wills <- will
+				// CONNACK 接受连接，之后的报文全部丢弃
+				_, _ = conn.Write([]byte{0x20, 0x02, 0x00, 0x00})
+				_, _ = io.Copy(io.Discard, conn)
+			}()
+		}
+	}()
+	return "tcp://" + listener.Addr().String(), wills
+}
+
+// readConnectWill 解析 CONNECT 报文中的遗嘱
+func readConnectWill(r *bufio.Reader) (mqttWill, error) {
+	var will mqttWill
+	if _, err := r.ReadByte(); err != nil {
+		return will, err
+	}
+	// 剩余长度
+	size, multiplier := 0, 1
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return will, err
+		}
+		size += int(b&0x7f) * multiplier
+		multiplier *= 128
+		if b&0x80 == 0 {
+			break
+		}
+	}
+	body := make([]byte, size)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return will, err
+	}
+	field := func() []byte {
+		n := int(binary.BigEndian.Uint16(body))
+		v := body[2 : 2+n]
+		body = body[2+n:]
+		return v
+	}
+	_ = field() // 协议名
+	flags := body[1]
+	body = body[4:] // 协议级别 连接标志 保持连接
+	_ = field()     // ClientId
+	if flags&0x04 == 0 {
+		return will, nil
+	}
+	will.Enabled = true
+	will.Qos = flags >> 3 & 0x03
+	will.Retain = flags&0x20 != 0
+	will.Topic = string(field())
+	will.Payload = append([]byte(nil), field()...)
+	return will, nil
+}
+
+// connectWill 连接并返回连接时的遗嘱
+func connectWill(t *testing.T, setting easyCon.MqttSetting, wills <-chan mqttWill) (easyCon.IAdapter, mqttWill) {
+	setting.IsWaitLink = false
+	adapter := easyCon.NewMqttAdapter(setting, easyCon.AdapterCallBack{})
+	return adapter, nextWill(t, wills)
+}
+
+func nextWill(t *testing.T, wills <-chan mqttWill) mqttWill {
+	select {
+	case will := <-wills:
+		return will
+	case <-time.After(time.Second * 3):
+		t.Fatalf("no connect")
+	}
+	return mqttWill{}
+}
+
+// decodeWill 解析遗嘱内容
+func decodeWill(t *testing.T, payload []byte) *easyCon.PackNotice {
+	pack, err := easyCon.UnmarshalPack(payload)
+	if err != nil {
+		t.Fatalf("unmarshal will: %v", err)
+	}
+	notice, ok := pack.(*easyCon.PackNotice)
+	if !ok {
+		t.Fatalf("will is %T", pack)
+	}
+	return notice
+}
+
+func TestWillDefaultOffline(t *testing.T) {
+	addr, wills := startWillBroker(t)
+	setting := easyCon.NewDefaultMqttSetting("WillModule", addr)
+	setting.PreFix = "A."
+	adapter, will := connectWill(t, setting, wills)
+	defer adapter.Stop()
+	if !will.Enabled || !will.Retain || will.Qos != 0 {
+		t.Fatalf("will %+v", will)
+	}
+	if want := easyCon.BuildRetainNoticeTopic("A.", easyCon.PresenceRoute+"/WillModule"); will.Topic != want {
+		t.Errorf("topic %s, want %s", will.Topic, want)
+	}
+	notice := decodeWill(t, will.Payload)
+	if notice.From != "WillModule" || notice.Route != easyCon.PresenceRoute+"/WillModule" || !notice.Retain {
+		t.Errorf("will %+v", notice)
+	}
+	var first easyCon.PresenceInfo
+	if err := json.Unmarshal(notice.Content, &first); err != nil || first.Module != "WillModule" || first.Online {
+		t.Errorf("presence %+v %v", first, err)
+	}
+
+	// 每次连接重新生成下线记录
+	time.Sleep(time.Millisecond * 20)
+	adapter.Reset()
+	var again easyCon.PresenceInfo
+	_ = json.Unmarshal(decodeWill(t, nextWill(t, wills).Payload).Content, &again)
+	if again.Time <= first.Time {
+		t.Errorf("will time not refreshed: %s then %s", first.Time, again.Time)
+	}
+}
+
+func TestWillCustom(t *testing.T) {
+	addr, wills := startWillBroker(t)
+	setting := easyCon.NewDefaultMqttSetting("WillModule", addr)
+	setting.WillPayload = &easyCon.PackNotice{Route: "Bye", Content: []byte("gone")}
+	setting.WillQos = 1
+	adapter, will := connectWill(t, setting, wills)
+	adapter.Stop()
+	if !will.Enabled || will.Retain || will.Qos != 1 || will.Topic != easyCon.BuildNoticeTopic("", "Bye") {
+		t.Fatalf("will %+v", will)
+	}
+	notice := decodeWill(t, will.Payload)
+	if notice.From != "WillModule" || string(notice.Content) != "gone" {
+		t.Errorf("will %+v", notice)
+	}
+
+	// 指定主题时原样使用
+	setting.WillTopic = "Custom/Will"
+	setting.WillRetain = true
+	adapter, will = connectWill(t, setting, wills)
+	adapter.Stop()
+	if !will.Enabled || !will.Retain || will.Topic != "Custom/Will" {
+		t.Errorf("custom topic %+v", will)
+	}
+
+	// 只有主题没有内容时不设置遗嘱
+	setting.WillPayload = nil
+	adapter, will = connectWill(t, setting, wills)
+	adapter.Stop()
+	if will.Enabled {
+		t.Errorf("will without payload should be skipped")
+	}
+}
+
+func TestWillDisabled(t *testing.T) {
+	addr, wills := startWillBroker(t)
+	setting := easyCon.NewDefaultMqttSetting("WillModule", addr)
+	setting.IsDisableWill = true
+	adapter, will := connectWill(t, setting, wills)
+	defer adapter.Stop()
+	if will.Enabled {
+		t.Errorf("disabled will was built")
+	}
+}
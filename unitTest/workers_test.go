@@ -0,0 +1,94 @@
+/**
+ * @Author: Joey
+ * @Description: 有界处理协程池与路由并发限制单元测试
+ * @Create Date: 2026/10/18 16:50
+ */
+
+package unitTest
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	easyCon "github.com/qiu-tec/easy-con.golang"
+)
+
+// concurrencyProbe 记录处理函数的最大并发数
+type concurrencyProbe struct {
+	running int32
+	peak    int32
+}
+
+func (probe *concurrencyProbe) handler(cost time.Duration) easyCon.ReqHandler {
+	return func(easyCon.PackReq) (easyCon.EResp, []byte) {
+		n := atomic.AddInt32(&probe.running, 1)
+		for {
+			peak := atomic.LoadInt32(&probe.peak)
+			if n <= peak || atomic.CompareAndSwapInt32(&probe.peak, peak, n) {
+				break
+			}
+		}
+		time.Sleep(cost)
+		atomic.AddInt32(&probe.running, -1)
+		return easyCon.ERespSuccess, nil
+	}
+}
+
+// burst 并发发出 n 个请求，返回成功数
+func burst(client easyCon.IAdapter, module, route string, n int) int32 {
+	var ok int32
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if resp := client.ReqWithTimeout(module, route, nil, 3000); resp.RespCode == easyCon.ERespSuccess {
+				atomic.AddInt32(&ok, 1)
+			}
+		}()
+	}
+	wg.Wait()
+	return ok
+}
+
+func TestReqWorkers(t *testing.T) {
+	broker := easyCon.NewCgoBroker()
+	client := newCgoModule(&broker, newCgoSetting("PoolClient"), easyCon.AdapterCallBack{})
+	setting := newCgoSetting("PoolServer")
+	setting.ReqWorkers = 3
+	server := newCgoModule(&broker, setting, easyCon.AdapterCallBack{})
+	probe := &concurrencyProbe{}
+	server.HandleFunc("Work", probe.handler(time.Millisecond*30))
+
+	if ok := burst(client, "PoolServer", "Work", 20); ok != 20 {
+		t.Fatalf("succeeded %d of 20", ok)
+	}
+	if peak := atomic.LoadInt32(&probe.peak); peak > 3 || peak < 2 {
+		t.Errorf("peak concurrency %d, want at most 3", peak)
+	}
+}
+
+func TestRouteConcurrency(t *testing.T) {
+	broker := easyCon.NewCgoBroker()
+	client := newCgoModule(&broker, newCgoSetting("LimitClient"), easyCon.AdapterCallBack{})
+	setting := newCgoSetting("LimitServer")
+	setting.RouteConcurrency = map[string]int{"Slow": 1}
+	server := newCgoModule(&broker, setting, easyCon.AdapterCallBack{})
+	slow, fast := &concurrencyProbe{}, &concurrencyProbe{}
+	server.HandleFunc("Slow", slow.handler(time.Millisecond*20))
+	server.HandleFunc("Fast", fast.handler(time.Millisecond*50))
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() { defer wg.Done(); burst(client, "LimitServer", "Slow", 5) }()
+	go func() { defer wg.Done(); burst(client, "LimitServer", "Fast", 5) }()
+	wg.Wait()
+	if peak := atomic.LoadInt32(&slow.peak); peak != 1 {
+		t.Errorf("limited route peak %d, want 1", peak)
+	}
+	if peak := atomic.LoadInt32(&fast.peak); peak < 2 {
+		t.Errorf("unlimited route peak %d, want parallel", peak)
+	}
+}
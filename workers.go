@@ -0,0 +1,129 @@
+/**
+ * @Author: Joey
+ * @Description: 异步模式下的有界处理协程池与路由并发限制
+ * @Create Date: 2026/10/18 16:30
+ */
+
+package easyCon
+
+import (
+	"context"
+	"hash/fnv"
+)
+
+// startWorkers 启动 n 个协程从 high 与 normal 中取消息处理，high 中有消息时优先处理
+// high 可为 nil，quit 关闭后处理完当前消息退出
+func startWorkers[T any](n int, high, normal <-chan T, handle func(T), quit <-chan struct{}) {
+	for i := 0; i < n; i++ {
+		go func() {
+			for {
+				msg, ok := nextMsg(high, normal, quit)
+				if !ok {
+					return
+				}
+				handle(msg)
+			}
+		}()
+	}
+}
+
+// nextMsg 取下一条消息，high 优先，quit 关闭时返回 false
+func nextMsg[T any](high, normal <-chan T, quit <-chan struct{}) (T, bool) {
+	select {
+	case msg := <-high:
+		return msg, true
+	default:
+	}
+	select {
+	case msg := <-high:
+		return msg, true
+	case msg := <-normal:
+		return msg, true
+	case <-quit:
+		var zero T
+		return zero, false
+	}
+}
+
+// EOrderKey 有序处理的分组方式
+type EOrderKey string
+
+const (
+	EOrderNone      EOrderKey = ""           // 不保证顺序
+	EOrderFrom      EOrderKey = "FROM"       // 同一发送模块的消息按到达顺序处理
+	EOrderRoute     EOrderKey = "ROUTE"      // 同一路由的消息按到达顺序处理
+	EOrderFromRoute EOrderKey = "FROM_ROUTE" // 同一发送模块的同一路由按到达顺序处理
+)
+
+// defaultOrderShards 未设置处理协程数时有序处理的分片数
+const defaultOrderShards = 16
+
+// orderKey 按设置取消息的分组键
+func (adapter *coreAdapter) orderKey(from, route string) string {
+	switch adapter.setting.OrderKey {
+	case EOrderFrom:
+		return from
+	case EOrderRoute:
+		return route
+	default:
+		return from + "/" + route
+	}
+}
+
+// startOrdered 启动 n 个分片协程，同一分组键的消息始终进入同一分片串行处理，不同分片并行
+// 分发时 high 优先，已进入分片的消息不再区分优先级
+func startOrdered[T any](n, bufferSize int, high, normal <-chan T, key func(T) string, handle func(T), quit <-chan struct{}) {
+	if n <= 0 {
+		n = defaultOrderShards
+	}
+	shards := make([]chan T, n)
+	for i := range shards {
+		shards[i] = make(chan T, bufferSize)
+		startWorkers(1, nil, shards[i], handle, quit)
+	}
+	go func() {
+		for {
+			msg, ok := nextMsg(high, normal, quit)
+			if !ok {
+				return
+			}
+			h := fnv.New32a()
+			_, _ = h.Write([]byte(key(msg)))
+			select {
+			case shards[h.Sum32()%uint32(n)] <- msg:
+			case <-quit:
+				return
+			}
+		}
+	}()
+}
+
+// routeLimiter 按路由限制同时处理的请求数
+type routeLimiter struct {
+	slots map[string]chan struct{}
+}
+
+func newRouteLimiter(limits map[string]int) *routeLimiter {
+	limiter := &routeLimiter{slots: make(map[string]chan struct{}, len(limits))}
+	for route, n := range limits {
+		if n > 0 {
+			limiter.slots[route] = make(chan struct{}, n)
+		}
+	}
+	return limiter
+}
+
+// acquire 占用路由的处理名额，ctx 结束前未取得时返回 false
+// 取得名额后须调用 release 归还
+func (limiter *routeLimiter) acquire(ctx context.Context, route string) (release func(), ok bool) {
+	slot, limited := limiter.slots[route]
+	if !limited {
+		return func() {}, true
+	}
+	select {
+	case slot <- struct{}{}:
+		return func() { <-slot }, true
+	case <-ctx.Done():
+		return nil, false
+	}
+}